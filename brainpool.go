@@ -0,0 +1,71 @@
+package mdoc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"math/big"
+
+	"github.com/alex-richards/go-mdoc/internal/brainpool"
+	"github.com/veraison/go-cose"
+)
+
+// isBrainpool reports whether c is one of the Brainpool curves, which crypto/ecdsa and
+// go-cose do not support.
+func isBrainpool(c elliptic.Curve) bool {
+	_, ok := c.(*brainpool.Curve)
+	return ok
+}
+
+// brainpoolVerifier verifies ECDSA signatures made with Brainpool keys, which the go-cose
+// verifiers reject.
+type brainpoolVerifier struct {
+	algorithm       cose.Algorithm
+	digestAlgorithm DigestAlgorithm
+	publicKey       ecdsa.PublicKey
+}
+
+func newBrainpoolVerifier(publicKey *PublicKey, params *CurveParams) (*brainpoolVerifier, error) {
+	cryptoPublicKey, err := publicKey.CryptoPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	ecdsaPublicKey, ok := cryptoPublicKey.(*ecdsa.PublicKey)
+	if !ok || ecdsaPublicKey.Curve != params.EllipticCurve() {
+		return nil, ErrUnsupportedCurve
+	}
+
+	digestAlgorithm, err := params.DigestAlgorithm()
+	if err != nil {
+		return nil, err
+	}
+
+	return &brainpoolVerifier{
+		algorithm:       coseAlgorithm(params.Curve),
+		digestAlgorithm: digestAlgorithm,
+		publicKey:       *ecdsaPublicKey,
+	}, nil
+}
+
+func (bv *brainpoolVerifier) Algorithm() cose.Algorithm {
+	return bv.algorithm
+}
+
+// Verify checks a signature encoded as r || s.
+func (bv *brainpoolVerifier) Verify(content, signature []byte) error {
+	size := (bv.publicKey.Params().BitSize + 7) / 8
+	if len(signature) != size*2 {
+		return cose.ErrVerification
+	}
+
+	digest, err := bv.digestAlgorithm.Sum(content)
+	if err != nil {
+		return err
+	}
+
+	r := new(big.Int).SetBytes(signature[:size])
+	s := new(big.Int).SetBytes(signature[size:])
+	if !ecdsa.Verify(&bv.publicKey, digest, r, s) {
+		return cose.ErrVerification
+	}
+	return nil
+}
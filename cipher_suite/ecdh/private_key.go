@@ -2,6 +2,7 @@ package ecdh
 
 import (
 	"crypto/ecdh"
+	"crypto/ecdsa"
 	"io"
 
 	"github.com/alex-richards/go-mdoc"
@@ -51,7 +52,7 @@ func NewPrivateKey(privateKey *ecdh.PrivateKey) (*mdoc.PrivateKey, error) {
 }
 
 func newPrivateKey(curve mdoc.Curve, privateKey *ecdh.PrivateKey) (*mdoc.PrivateKey, error) {
-	publicKey, err := toPublicKey(privateKey.PublicKey())
+	publicKey, err := mdoc.NewPublicKey(privateKey.PublicKey())
 	if err != nil {
 		return nil, err
 	}
@@ -73,10 +74,26 @@ func (a *agreer) Curve() mdoc.Curve {
 }
 
 func (a *agreer) Agree(deviceKey *mdoc.PublicKey) ([]byte, error) {
-	remote, err := fromPublicKey(deviceKey)
+	publicKey, err := deviceKey.CryptoPublicKey()
 	if err != nil {
 		return nil, err
 	}
 
+	var remote *ecdh.PublicKey
+	switch publicKey := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if remote, err = publicKey.ECDH(); err != nil {
+			return nil, mdoc.ErrUnsupportedCurve
+		}
+	case *ecdh.PublicKey:
+		remote = publicKey
+	default:
+		return nil, mdoc.ErrUnsupportedCurve
+	}
+
+	if remote.Curve() != a.privateKey.Curve() {
+		return nil, mdoc.ErrUnsupportedCurve
+	}
+
 	return a.privateKey.ECDH(remote)
 }
@@ -1,26 +1,32 @@
 package ecdsa
 
 import (
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"encoding/asn1"
+	"errors"
 	"io"
 	"math/big"
 
 	"github.com/alex-richards/go-mdoc"
+	"github.com/alex-richards/go-mdoc/internal/brainpool"
 )
 
-// GeneratePrivateKey generates a new private key for use with go-mdoc.
+var (
+	ErrInvalidSignature = errors.New("mdoc: ecdsa: invalid signature")
+)
+
+// GeneratePrivateKey generates a new private key for use with go-mdoc. The Brainpool
+// curves are not supported, see NewSigner.
 func GeneratePrivateKey(rand io.Reader, curve mdoc.Curve) (*mdoc.PrivateKey, error) {
-	var c elliptic.Curve
-	switch curve {
-	case mdoc.CurveP256:
-		c = elliptic.P256()
-	case mdoc.CurveP384:
-		c = elliptic.P384()
-	case mdoc.CurveP521:
-		c = elliptic.P521()
-	default:
+	params, err := curve.Params()
+	if err != nil {
+		return nil, err
+	}
+
+	c := params.EllipticCurve()
+	if c == nil || isBrainpool(c) {
 		return nil, mdoc.ErrUnsupportedCurve
 	}
 
@@ -32,55 +38,99 @@ func GeneratePrivateKey(rand io.Reader, curve mdoc.Curve) (*mdoc.PrivateKey, err
 	return newPrivateKey(curve, privateKey)
 }
 
-// NewPrivateKey wraps an existing private key for use with go-mdoc.
+// NewPrivateKey wraps an existing private key for use with go-mdoc. The Brainpool curves
+// are not supported, see NewSigner.
 func NewPrivateKey(privateKey *ecdsa.PrivateKey) (*mdoc.PrivateKey, error) {
-	var curve mdoc.Curve
-	switch privateKey.Curve {
-	case elliptic.P256():
-		curve = mdoc.CurveP256
-	case elliptic.P384():
-		curve = mdoc.CurveP384
-	case elliptic.P521():
-		curve = mdoc.CurveP521
-	default:
+	curve, err := curveFromElliptic(privateKey.Curve)
+	if err != nil {
+		return nil, err
+	}
+	if isBrainpool(privateKey.Curve) {
 		return nil, mdoc.ErrUnsupportedCurve
 	}
 
 	return newPrivateKey(curve, privateKey)
 }
 
+func curveFromElliptic(c elliptic.Curve) (mdoc.Curve, error) {
+	params, err := mdoc.FindCurveParams(func(params *mdoc.CurveParams) bool {
+		return c != nil && params.EllipticCurve() == c
+	})
+	if err != nil {
+		return "", err
+	}
+	return params.Curve, nil
+}
+
+// isBrainpool reports whether c is a Brainpool curve. The Brainpool arithmetic of go-mdoc
+// is not constant time, so go-mdoc has no Brainpool private keys.
+func isBrainpool(c elliptic.Curve) bool {
+	_, ok := c.(*brainpool.Curve)
+	return ok
+}
+
+// NewSigner adapts a crypto.Signer with an ECDSA public key, such as a KMS or HSM client,
+// for use with go-mdoc. The message is hashed before signing, and the ASN.1 signature
+// returned by the signer is converted to the r || s form COSE uses.
+//
+// Brainpool keys are rejected whatever the signer, as a crypto.Signer wrapping an in-memory
+// key would sign with the variable time arithmetic. Brainpool signing is only available
+// from PKCS#11 tokens, see the pkcs11 package.
+func NewSigner(cryptoSigner crypto.Signer) (*mdoc.PrivateKey, error) {
+	publicKey, ok := cryptoSigner.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return nil, mdoc.ErrUnsupportedCurve
+	}
+
+	curve, err := curveFromElliptic(publicKey.Curve)
+	if err != nil {
+		return nil, err
+	}
+	if isBrainpool(publicKey.Curve) {
+		return nil, mdoc.ErrUnsupportedCurve
+	}
+
+	return newSigner(curve, cryptoSigner, publicKey)
+}
+
 func newPrivateKey(curve mdoc.Curve, privateKey *ecdsa.PrivateKey) (*mdoc.PrivateKey, error) {
-	var digestAlgorithm mdoc.DigestAlgorithm
+	return newSigner(curve, privateKey, &privateKey.PublicKey)
+}
+
+func newSigner(curve mdoc.Curve, cryptoSigner crypto.Signer, publicKey *ecdsa.PublicKey) (*mdoc.PrivateKey, error) {
+	var hash crypto.Hash
 	switch curve {
 	case mdoc.CurveP256:
-		digestAlgorithm = mdoc.DigestAlgorithmSHA256
+		hash = crypto.SHA256
 	case mdoc.CurveP384:
-		digestAlgorithm = mdoc.DigestAlgorithmSHA384
+		hash = crypto.SHA384
 	case mdoc.CurveP521:
-		digestAlgorithm = mdoc.DigestAlgorithmSHA512
+		hash = crypto.SHA512
 	default:
 		return nil, mdoc.ErrUnsupportedCurve
 	}
 
-	publicKey, err := toPublicKey(&privateKey.PublicKey)
+	pk, err := mdoc.NewPublicKey(publicKey)
 	if err != nil {
 		return nil, err
 	}
 
 	return &mdoc.PrivateKey{
 		Signer: signer{
-			privateKey:      *privateKey,
-			curve:           curve,
-			digestAlgorithm: digestAlgorithm,
+			signer: cryptoSigner,
+			curve:  curve,
+			hash:   hash,
+			size:   (publicKey.Params().BitSize + 7) / 8,
 		},
-		PublicKey: *publicKey,
+		PublicKey: *pk,
 	}, nil
 }
 
 type signer struct {
-	privateKey      ecdsa.PrivateKey
-	curve           mdoc.Curve
-	digestAlgorithm mdoc.DigestAlgorithm
+	signer crypto.Signer
+	curve  mdoc.Curve
+	hash   crypto.Hash
+	size   int
 }
 
 func (s signer) Curve() mdoc.Curve {
@@ -88,41 +138,39 @@ func (s signer) Curve() mdoc.Curve {
 }
 
 func (s signer) Sign(rand io.Reader, message []byte) ([]byte, error) {
-	sum, err := s.digestAlgorithm.Sum(message)
-	if err != nil {
-		return nil, err
-	}
+	h := s.hash.New()
+	h.Write(message)
+	sum := h.Sum(nil)
 
-	signatureASN1, err := ecdsa.SignASN1(rand, &s.privateKey, sum)
+	signatureASN1, err := s.signer.Sign(rand, sum, s.hash)
 	if err != nil {
 		return nil, err
 	}
 
-	return signatureASN1ToConcat(s.privateKey.Params(), signatureASN1), nil
+	return signatureASN1ToConcat(s.size, signatureASN1)
 }
 
-func signatureASN1ToConcat(params *elliptic.CurveParams, signatureASN1 []byte) []byte {
-	type ECDSASignature struct {
+// signatureASN1ToConcat converts an ASN.1 DER encoded ECDSA signature to the fixed length
+// r || s form COSE uses, where size is the length in bytes of the curve order.
+func signatureASN1ToConcat(size int, signatureASN1 []byte) ([]byte, error) {
+	var signatureECDSA struct {
 		R *big.Int
 		S *big.Int
 	}
 
-	signatureECDSA := new(ECDSASignature)
-	rest, err := asn1.Unmarshal(signatureASN1, signatureECDSA)
+	rest, err := asn1.Unmarshal(signatureASN1, &signatureECDSA)
 	if err != nil || len(rest) != 0 {
-		panic("unexpected signature format")
+		return nil, ErrInvalidSignature
 	}
 
-	size := (params.BitSize + 7) / 8
-	bytes := make([]byte, size)
+	r, s := signatureECDSA.R, signatureECDSA.S
+	if r.Sign() <= 0 || s.Sign() <= 0 || len(r.Bytes()) > size || len(s.Bytes()) > size {
+		return nil, ErrInvalidSignature
+	}
 
 	concatSignature := make([]byte, size*2)
+	r.FillBytes(concatSignature[:size])
+	s.FillBytes(concatSignature[size:])
 
-	signatureECDSA.R.FillBytes(bytes)
-	copy(concatSignature, bytes)
-
-	signatureECDSA.S.FillBytes(bytes)
-	copy(concatSignature[size:], bytes)
-
-	return concatSignature
+	return concatSignature, nil
 }
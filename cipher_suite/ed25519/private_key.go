@@ -1,6 +1,7 @@
 package ed25519
 
 import (
+	"crypto"
 	"crypto/ed25519"
 	"io"
 
@@ -27,8 +28,28 @@ func NewPrivateKey(privateKey ed25519.PrivateKey) (*mdoc.PrivateKey, error) {
 	return newPrivateKey(publicKey, privateKey)
 }
 
+// NewSigner adapts a crypto.Signer with an Ed25519 public key, such as a KMS or HSM client,
+// for use with go-mdoc.
+func NewSigner(cryptoSigner crypto.Signer) (*mdoc.PrivateKey, error) {
+	publicKey, ok := cryptoSigner.Public().(ed25519.PublicKey)
+	if !ok {
+		return nil, mdoc.ErrUnsupportedCurve
+	}
+
+	pk, err := mdoc.NewPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mdoc.PrivateKey{
+		Signer:    cryptoSignerAdapter{cryptoSigner},
+		Agreer:    nil,
+		PublicKey: *pk,
+	}, nil
+}
+
 func newPrivateKey(publicKey ed25519.PublicKey, privateKey ed25519.PrivateKey) (*mdoc.PrivateKey, error) {
-	pk, err := toPublicKey(publicKey)
+	pk, err := mdoc.NewPublicKey(publicKey)
 	if err != nil {
 		return nil, err
 	}
@@ -49,3 +70,15 @@ func (s signer) Curve() mdoc.Curve {
 func (s signer) Sign(_ io.Reader, message []byte) ([]byte, error) {
 	return ed25519.Sign((ed25519.PrivateKey)(s), message), nil
 }
+
+type cryptoSignerAdapter struct {
+	signer crypto.Signer
+}
+
+func (s cryptoSignerAdapter) Curve() mdoc.Curve {
+	return mdoc.CurveEd25519
+}
+
+func (s cryptoSignerAdapter) Sign(rand io.Reader, message []byte) ([]byte, error) {
+	return s.signer.Sign(rand, message, crypto.Hash(0))
+}
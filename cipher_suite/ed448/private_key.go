@@ -1,6 +1,7 @@
 package ed448
 
 import (
+	"crypto"
 	"io"
 
 	"github.com/alex-richards/go-mdoc"
@@ -20,7 +21,7 @@ func GeneratePrivateKey(rand io.Reader) (*mdoc.PrivateKey, error) {
 
 // NewPrivateKey wraps an existing private key for use with go-mdoc.
 func NewPrivateKey(privateKey ed448.PrivateKey) (*mdoc.PrivateKey, error) {
-	publicKey, err := toPublicKey(privateKey.Public().(ed448.PublicKey))
+	publicKey, err := mdoc.NewPublicKey(privateKey.Public().(ed448.PublicKey))
 	if err != nil {
 		return nil, err
 	}
@@ -32,6 +33,26 @@ func NewPrivateKey(privateKey ed448.PrivateKey) (*mdoc.PrivateKey, error) {
 	}, nil
 }
 
+// NewSigner adapts a crypto.Signer with an Ed448 public key, such as a KMS or HSM client,
+// for use with go-mdoc.
+func NewSigner(cryptoSigner crypto.Signer) (*mdoc.PrivateKey, error) {
+	publicKey, ok := cryptoSigner.Public().(ed448.PublicKey)
+	if !ok {
+		return nil, mdoc.ErrUnsupportedCurve
+	}
+
+	pk, err := mdoc.NewPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mdoc.PrivateKey{
+		Signer:    cryptoSignerAdapter{cryptoSigner},
+		Agreer:    nil,
+		PublicKey: *pk,
+	}, nil
+}
+
 type signer ed448.PrivateKey
 
 func (s signer) Curve() mdoc.Curve {
@@ -41,3 +62,15 @@ func (s signer) Curve() mdoc.Curve {
 func (s signer) Sign(_ io.Reader, data []byte) ([]byte, error) {
 	return ed448.Sign((ed448.PrivateKey)(s), data, ""), nil
 }
+
+type cryptoSignerAdapter struct {
+	signer crypto.Signer
+}
+
+func (s cryptoSignerAdapter) Curve() mdoc.Curve {
+	return mdoc.CurveEd448
+}
+
+func (s cryptoSignerAdapter) Sign(rand io.Reader, message []byte) ([]byte, error) {
+	return s.signer.Sign(rand, message, crypto.Hash(0))
+}
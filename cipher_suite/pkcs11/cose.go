@@ -0,0 +1,84 @@
+package pkcs11
+
+import (
+	"encoding/asn1"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/veraison/go-cose"
+)
+
+func curveParamsFromCurve(curve mdoc.Curve) (*mdoc.CurveParams, error) {
+	return mdoc.FindCurveParams(func(params *mdoc.CurveParams) bool {
+		return params.KeyType == cose.KeyTypeEC2 && params.Curve == curve
+	})
+}
+
+// curveParamsFromECParams maps the DER encoded CKA_EC_PARAMS of a key, which must name
+// the curve, to its parameters.
+func curveParamsFromECParams(ecParams []byte) (*mdoc.CurveParams, error) {
+	var oid asn1.ObjectIdentifier
+	rest, err := asn1.Unmarshal(ecParams, &oid)
+	if err != nil || len(rest) != 0 {
+		return nil, mdoc.ErrUnsupportedCurve
+	}
+
+	return mdoc.FindCurveParams(func(params *mdoc.CurveParams) bool {
+		return params.KeyType == cose.KeyTypeEC2 && params.OID.Equal(oid)
+	})
+}
+
+// uncompressedPoint returns the uncompressed point from a CKA_EC_POINT, which tokens
+// either DER encode as an octet string, as PKCS #11 requires, or return raw.
+func uncompressedPoint(params *mdoc.CurveParams, ecPoint []byte) ([]byte, error) {
+	// The raw form is checked first, as it starts with 4, the OCTET STRING tag, and may
+	// also parse as DER.
+	if len(ecPoint) == 1+2*params.Size && ecPoint[0] == 4 {
+		return ecPoint, nil
+	}
+
+	var point []byte
+	if rest, err := asn1.Unmarshal(ecPoint, &point); err != nil || len(rest) != 0 {
+		return nil, ErrInvalidPublicKey
+	}
+
+	if len(point) != 1+2*params.Size || point[0] != 4 {
+		return nil, ErrInvalidPublicKey
+	}
+	return point, nil
+}
+
+func toPublicKey(params *mdoc.CurveParams, point []byte, sign bool) *mdoc.PublicKey {
+	x := make([]byte, params.Size)
+	copy(x, point[1:params.Size+1])
+
+	y := make([]byte, params.Size)
+	copy(y, point[1+params.Size:])
+
+	alg := cose.AlgorithmReserved
+	if sign {
+		alg = params.Algorithm()
+	}
+
+	return &mdoc.PublicKey{
+		Type:      cose.KeyTypeEC2,
+		Algorithm: alg,
+		Params: map[any]any{
+			cose.KeyLabelEC2Curve: params.COSECurve,
+			cose.KeyLabelEC2X:     x,
+			cose.KeyLabelEC2Y:     y,
+		},
+	}
+}
+
+func fromPublicKey(params *mdoc.CurveParams, key *mdoc.PublicKey) ([]byte, error) {
+	if key.Type != cose.KeyTypeEC2 || key.Params[cose.KeyLabelEC2Curve] != params.COSECurve {
+		return nil, mdoc.ErrUnsupportedCurve
+	}
+
+	// check the point is on the curve before passing it to the token
+	if _, err := key.CryptoPublicKey(); err != nil {
+		return nil, err
+	}
+
+	return key.UncompressedPoint()
+}
@@ -0,0 +1,129 @@
+package pkcs11
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"errors"
+	"testing"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/alex-richards/go-mdoc/internal/testutil"
+	"github.com/veraison/go-cose"
+)
+
+func Test_curveParamsFromECParams(t *testing.T) {
+	for _, curve := range testCurves {
+		t.Run(curve.Name(), func(t *testing.T) {
+			params, err := curveParamsFromCurve(curve)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ecParams, err := asn1.Marshal(params.OID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := curveParamsFromECParams(ecParams)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Curve != curve {
+				t.Fatalf("expected %s, got %s", curve, got.Curve)
+			}
+		})
+	}
+
+	t.Run("Unsupported", func(t *testing.T) {
+		ecParams, err := asn1.Marshal(asn1.ObjectIdentifier{1, 3, 101, 112})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = curveParamsFromECParams(ecParams); !errors.Is(err, mdoc.ErrUnsupportedCurve) {
+			t.Fatalf("expected %v, got %v", mdoc.ErrUnsupportedCurve, err)
+		}
+	})
+}
+
+func Test_uncompressedPoint(t *testing.T) {
+	params, err := curveParamsFromCurve(mdoc.CurveP256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	point := make([]byte, 1+2*params.Size)
+	point[0] = 4
+	point[1] = 1
+
+	der, err := asn1.Marshal(point)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a raw point whose first byte of x, 0x3f, also reads as a DER length of 63
+	ambiguousPoint := bytes.Clone(point)
+	ambiguousPoint[1] = 0x3f
+
+	tests := []struct {
+		name    string
+		ecPoint []byte
+		want    []byte
+		wantErr error
+	}{
+		{name: "DER", ecPoint: der, want: point},
+		{name: "Raw", ecPoint: point, want: point},
+		{name: "Raw DER Prefix", ecPoint: ambiguousPoint, want: ambiguousPoint},
+		{name: "Compressed", ecPoint: append([]byte{2}, point[1:params.Size+1]...), wantErr: ErrInvalidPublicKey},
+		{name: "Empty", ecPoint: nil, wantErr: ErrInvalidPublicKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := uncompressedPoint(params, tt.ecPoint)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("expected %x, got %x", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_fromPublicKey_ShortCoordinate(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	params, err := curveParamsFromCurve(mdoc.CurveP256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// find a key with a leading zero in x, which some encoders omit
+	var privateKey *ecdsa.PrivateKey
+	for privateKey == nil || len(privateKey.X.Bytes()) == params.Size {
+		if privateKey, err = ecdsa.GenerateKey(params.EllipticCurve(), rand); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	publicKey, err := mdoc.NewPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey.Params[cose.KeyLabelEC2X] = privateKey.X.Bytes()
+
+	want := make([]byte, 1+2*params.Size)
+	want[0] = 4
+	privateKey.X.FillBytes(want[1 : 1+params.Size])
+	privateKey.Y.FillBytes(want[1+params.Size:])
+
+	got, err := fromPublicKey(params, publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("expected %x, got %x", want, got)
+	}
+}
@@ -0,0 +1,285 @@
+// Package pkcs11 implements mdoc.Signer and mdoc.Agreer with EC keys held on a PKCS #11
+// token, such as an HSM.
+package pkcs11
+
+import (
+	"encoding/asn1"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/miekg/pkcs11"
+)
+
+var (
+	ErrKeyNotFound      = errors.New("mdoc: pkcs11: key not found")
+	ErrAmbiguousKey     = errors.New("mdoc: pkcs11: more than one key found")
+	ErrInvalidPublicKey = errors.New("mdoc: pkcs11: invalid public key")
+	ErrInvalidSignature = errors.New("mdoc: pkcs11: invalid signature")
+	ErrInvalidSecret    = errors.New("mdoc: pkcs11: invalid shared secret")
+)
+
+// Session is an open, logged in, PKCS #11 session. Sessions may not be used concurrently,
+// so operations on the keys of a Session are serialised.
+type Session struct {
+	ctx    *pkcs11.Ctx
+	handle pkcs11.SessionHandle
+	mutex  sync.Mutex
+}
+
+// NewSession wraps an open session, which the caller remains responsible for closing once
+// the keys are no longer used.
+func NewSession(ctx *pkcs11.Ctx, handle pkcs11.SessionHandle) *Session {
+	return &Session{
+		ctx:    ctx,
+		handle: handle,
+	}
+}
+
+// FindPrivateKey finds the EC key pair with label on the token, for signing if sign is
+// set, or for key agreement otherwise.
+func (s *Session) FindPrivateKey(label string, sign bool) (*mdoc.PrivateKey, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	privateKey, err := s.findObject(pkcs11.CKO_PRIVATE_KEY, label)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := s.findObject(pkcs11.CKO_PUBLIC_KEY, label)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newPrivateKey(privateKey, publicKey, sign)
+}
+
+// NewPrivateKey wraps an EC key pair on the token, for signing if sign is set, or for key
+// agreement otherwise.
+func (s *Session) NewPrivateKey(privateKey, publicKey pkcs11.ObjectHandle, sign bool) (*mdoc.PrivateKey, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.newPrivateKey(privateKey, publicKey, sign)
+}
+
+// GeneratePrivateKey generates a new EC key pair on the token, for signing if sign is set,
+// or for key agreement otherwise. The keys are session objects, and are destroyed when the
+// session is closed.
+func (s *Session) GeneratePrivateKey(curve mdoc.Curve, label string, sign bool) (*mdoc.PrivateKey, error) {
+	params, err := curveParamsFromCurve(curve)
+	if err != nil {
+		return nil, err
+	}
+
+	ecParams, err := asn1.Marshal(params.OID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	publicKey, privateKey, err := s.ctx.GenerateKeyPair(
+		s.handle,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+			pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, ecParams),
+			pkcs11.NewAttribute(pkcs11.CKA_VERIFY, sign),
+			pkcs11.NewAttribute(pkcs11.CKA_DERIVE, !sign),
+		},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+			pkcs11.NewAttribute(pkcs11.CKA_SIGN, sign),
+			pkcs11.NewAttribute(pkcs11.CKA_DERIVE, !sign),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newPrivateKey(privateKey, publicKey, sign)
+}
+
+func (s *Session) findObject(class uint, label string) (pkcs11.ObjectHandle, error) {
+	err := s.ctx.FindObjectsInit(s.handle, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	objects, _, err := s.ctx.FindObjects(s.handle, 2)
+	if finalErr := s.ctx.FindObjectsFinal(s.handle); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	switch len(objects) {
+	case 0:
+		return 0, ErrKeyNotFound
+	case 1:
+		return objects[0], nil
+	default:
+		return 0, ErrAmbiguousKey
+	}
+}
+
+func (s *Session) newPrivateKey(privateKey, publicKey pkcs11.ObjectHandle, sign bool) (*mdoc.PrivateKey, error) {
+	attributes, err := s.ctx.GetAttributeValue(s.handle, publicKey, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, nil),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ecParams, ecPoint []byte
+	for _, attribute := range attributes {
+		switch attribute.Type {
+		case pkcs11.CKA_EC_PARAMS:
+			ecParams = attribute.Value
+		case pkcs11.CKA_EC_POINT:
+			ecPoint = attribute.Value
+		}
+	}
+
+	params, err := curveParamsFromECParams(ecParams)
+	if err != nil {
+		return nil, err
+	}
+
+	point, err := uncompressedPoint(params, ecPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &key{
+		session:    s,
+		params:     params,
+		privateKey: privateKey,
+	}
+
+	if sign {
+		return &mdoc.PrivateKey{
+			Signer:    &signer{k},
+			PublicKey: *toPublicKey(params, point, true),
+		}, nil
+	}
+
+	return &mdoc.PrivateKey{
+		Agreer:    &agreer{k},
+		PublicKey: *toPublicKey(params, point, false),
+	}, nil
+}
+
+type key struct {
+	session    *Session
+	params     *mdoc.CurveParams
+	privateKey pkcs11.ObjectHandle
+}
+
+func (k *key) Curve() mdoc.Curve {
+	return k.params.Curve
+}
+
+type signer struct {
+	*key
+}
+
+// Sign hashes message and signs the digest on the token, which returns the signature in
+// the r || s form COSE uses.
+func (s *signer) Sign(_ io.Reader, message []byte) ([]byte, error) {
+	digestAlgorithm, err := s.params.DigestAlgorithm()
+	if err != nil {
+		return nil, err
+	}
+
+	sum, err := digestAlgorithm.Sum(message)
+	if err != nil {
+		return nil, err
+	}
+
+	s.session.mutex.Lock()
+	defer s.session.mutex.Unlock()
+
+	err = s.session.ctx.SignInit(
+		s.session.handle,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)},
+		s.privateKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := s.session.ctx.Sign(s.session.handle, sum)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(signature) != 2*s.params.Size {
+		return nil, ErrInvalidSignature
+	}
+	return signature, nil
+}
+
+type agreer struct {
+	*key
+}
+
+// Agree derives the shared secret on the token, returning the x coordinate of the shared
+// point. The derived secret is destroyed once read.
+func (a *agreer) Agree(publicKey *mdoc.PublicKey) ([]byte, error) {
+	point, err := fromPublicKey(a.params, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	a.session.mutex.Lock()
+	defer a.session.mutex.Unlock()
+
+	sharedSecret, err := a.session.ctx.DeriveKey(
+		a.session.handle,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(
+			pkcs11.CKM_ECDH1_DERIVE,
+			pkcs11.NewECDH1DeriveParams(pkcs11.CKD_NULL, nil, point),
+		)},
+		a.privateKey,
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+			pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_GENERIC_SECRET),
+			pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, a.params.Size),
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, false),
+			pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, true),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer a.session.ctx.DestroyObject(a.session.handle, sharedSecret)
+
+	attributes, err := a.session.ctx.GetAttributeValue(a.session.handle, sharedSecret, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, nil),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(attributes) != 1 || len(attributes[0].Value) != a.params.Size {
+		return nil, ErrInvalidSecret
+	}
+
+	return attributes[0].Value, nil
+}
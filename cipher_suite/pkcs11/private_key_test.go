@@ -0,0 +1,157 @@
+package pkcs11
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/alex-richards/go-mdoc"
+	mdocecdh "github.com/alex-richards/go-mdoc/cipher_suite/ecdh"
+	"github.com/alex-richards/go-mdoc/internal/testutil"
+	"github.com/miekg/pkcs11"
+	"github.com/veraison/go-cose"
+)
+
+// newTestSession opens a session on the first token of the module named by
+// MDOC_PKCS11_MODULE, such as SoftHSM, logging in with MDOC_PKCS11_PIN.
+func newTestSession(t *testing.T) *Session {
+	t.Helper()
+
+	module := os.Getenv("MDOC_PKCS11_MODULE")
+	if module == "" {
+		t.Skip("MDOC_PKCS11_MODULE not set")
+	}
+
+	ctx := pkcs11.New(module)
+	if ctx == nil {
+		t.Fatalf("failed to load %s", module)
+	}
+	if err := ctx.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = ctx.Finalize()
+		ctx.Destroy()
+	})
+
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slots) == 0 {
+		t.Fatal("no token")
+	}
+
+	handle, err := ctx.OpenSession(slots[0], pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = ctx.CloseSession(handle)
+	})
+
+	if err = ctx.Login(handle, pkcs11.CKU_USER, os.Getenv("MDOC_PKCS11_PIN")); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewSession(ctx, handle)
+}
+
+var testCurves = []mdoc.Curve{
+	mdoc.CurveP256,
+	mdoc.CurveP384,
+	mdoc.CurveP521,
+	mdoc.CurveBrainpoolP256r1,
+	mdoc.CurveBrainpoolP320r1,
+	mdoc.CurveBrainpoolP384r1,
+	mdoc.CurveBrainpoolP512r1,
+}
+
+func Test_Signer(t *testing.T) {
+	session := newTestSession(t)
+	message := []byte{1, 2, 3, 4}
+
+	for _, curve := range testCurves {
+		t.Run(curve.Name(), func(t *testing.T) {
+			privateKey, err := session.GeneratePrivateKey(curve, "Test_Signer", true)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			signature, err := privateKey.Signer.Sign(nil, message)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			params, err := curveParamsFromCurve(curve)
+			if err != nil {
+				t.Fatal(err)
+			}
+			publicKey := &ecdsa.PublicKey{
+				Curve: params.EllipticCurve(),
+				X:     new(big.Int).SetBytes(privateKey.PublicKey.Params[cose.KeyLabelEC2X].([]byte)),
+				Y:     new(big.Int).SetBytes(privateKey.PublicKey.Params[cose.KeyLabelEC2Y].([]byte)),
+			}
+
+			digestAlgorithm, err := params.DigestAlgorithm()
+			if err != nil {
+				t.Fatal(err)
+			}
+			sum, err := digestAlgorithm.Sum(message)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			size := len(signature) / 2
+			r := new(big.Int).SetBytes(signature[:size])
+			s := new(big.Int).SetBytes(signature[size:])
+			if !ecdsa.Verify(publicKey, sum, r, s) {
+				t.Fatal("signature does not verify")
+			}
+		})
+	}
+}
+
+func Test_Agreer(t *testing.T) {
+	session := newTestSession(t)
+	rand := testutil.NewDeterministicRand(t)
+
+	for _, curve := range testCurves {
+		t.Run(curve.Name(), func(t *testing.T) {
+			privateKey, err := session.GeneratePrivateKey(curve, "Test_Agreer", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			remoteKey, err := mdocecdh.GeneratePrivateKey(rand, curve)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sharedSecret, err := privateKey.Agreer.Agree(&remoteKey.PublicKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			remoteSharedSecret, err := remoteKey.Agreer.Agree(&privateKey.PublicKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(sharedSecret, remoteSharedSecret) {
+				t.Fatal("shared secrets differ")
+			}
+		})
+	}
+}
+
+func Test_Session_FindPrivateKey_NotFound(t *testing.T) {
+	session := newTestSession(t)
+
+	if _, err := session.FindPrivateKey("Test_Session_FindPrivateKey_NotFound", true); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected %v, got %v", ErrKeyNotFound, err)
+	}
+}
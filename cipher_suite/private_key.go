@@ -45,6 +45,22 @@ func NewPrivateKey(curve mdoc.Curve, privateKey crypto.PrivateKey) (*mdoc.Privat
 	}
 }
 
+// NewSigner adapts a crypto.Signer, such as a KMS, HSM or smartcard client, for use with
+// go-mdoc. The curve and algorithm are taken from the public key of the signer. Brainpool
+// keys are not supported here; sign with a PKCS#11 token instead.
+func NewSigner(signer crypto.Signer) (*mdoc.PrivateKey, error) {
+	switch signer.Public().(type) {
+	case *ecdsa.PublicKey:
+		return mdocecdsa.NewSigner(signer)
+	case ed25519.PublicKey:
+		return mdoced25519.NewSigner(signer)
+	case ed448.PublicKey:
+		return mdoced448.NewSigner(signer)
+	default:
+		return nil, ErrUnsupportedPrivateKey
+	}
+}
+
 func GeneratePrivateKey(rand io.Reader, curve mdoc.Curve, sign bool) (*mdoc.PrivateKey, error) {
 	if sign {
 		switch curve {
@@ -23,7 +23,7 @@ func NewPrivateKey(privateKey *x448.Key) (*mdoc.PrivateKey, error) {
 	var publicKey x448.Key
 	x448.KeyGen(&publicKey, privateKey)
 
-	pk, err := toPublicKey(&publicKey)
+	pk, err := mdoc.NewPublicKey(&publicKey)
 	if err != nil {
 		return nil, err
 	}
@@ -42,13 +42,20 @@ func (a agreer) Curve() mdoc.Curve {
 }
 
 func (a agreer) Agree(deviceKey *mdoc.PublicKey) ([]byte, error) {
-	remote, err := fromPublicKey(deviceKey)
+	publicKey, err := deviceKey.CryptoPublicKey()
 	if err != nil {
 		return nil, err
 	}
 
+	remote, ok := publicKey.(*x448.Key)
+	if !ok {
+		return nil, mdoc.ErrUnsupportedCurve
+	}
+
 	var shared x448.Key
-	x448.Shared(&shared, (*x448.Key)(&a), remote)
+	if !x448.Shared(&shared, (*x448.Key)(&a), remote) {
+		return nil, mdoc.ErrInvalidPublicKey
+	}
 
 	return shared[:], nil
 }
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/jawher/mow.cli"
+	"rsc.io/qr"
+)
+
+func cmdDeviceEngagement(cmd *cli.Cmd) {
+	cmd.Command("qr", "Render a Device Engagement URI as a QR code.", cmdDeviceEngagementQR)
+	cmd.Command("decode", "Decode a Device Engagement URI.", cmdDeviceEngagementDecode)
+}
+
+func cmdDeviceEngagementQR(cmd *cli.Cmd) {
+	cmd.Spec = "URI [OPTIONS]"
+
+	uri := cmd.StringArg("URI", "", "Device Engagement \"mdoc:\" URI.")
+
+	png := cmd.BoolOpt("p png", false, "Output a PNG image rather than rendering to the terminal.")
+
+	out := &WriterValue{
+		value:      "-",
+		withStdout: true,
+	}
+	cmd.VarOpt("o out-file", out, "Output file, defaults to stdout.")
+
+	cmd.Action = func() {
+		if _, _, err := mdoc.ParseDeviceEngagementURI(*uri); err != nil {
+			log.Fatal(err)
+		}
+
+		outWriteCloser, err := out.Open()
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer outWriteCloser.Close()
+
+		cmdDeviceEngagementQRAction(*uri, *png, outWriteCloser)
+	}
+}
+
+func cmdDeviceEngagementQRAction(uri string, png bool, writer io.Writer) {
+	code, err := qr.Encode(uri, qr.M)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if png {
+		if _, err = writer.Write(code.PNG()); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// two modules per character using half blocks, with the 4 module quiet zone required
+	// by ISO/IEC 18004
+	const quietZone = 4
+	var sb strings.Builder
+	for y := -quietZone; y < code.Size+quietZone; y += 2 {
+		for x := -quietZone; x < code.Size+quietZone; x++ {
+			top := code.Black(x, y)
+			bottom := code.Black(x, y+1)
+			switch {
+			case top && bottom:
+				sb.WriteRune(' ')
+			case top:
+				sb.WriteRune('▄')
+			case bottom:
+				sb.WriteRune('▀')
+			default:
+				sb.WriteRune('█')
+			}
+		}
+		sb.WriteRune('\n')
+	}
+
+	if _, err = io.WriteString(writer, sb.String()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func cmdDeviceEngagementDecode(cmd *cli.Cmd) {
+	cmd.Spec = "URI"
+
+	uri := cmd.StringArg("URI", "", "Device Engagement \"mdoc:\" URI.")
+
+	cmd.Action = func() {
+		_, deviceEngagementBytes, err := mdoc.ParseDeviceEngagementURI(*uri)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		diagnostic, err := cbor.Diagnose(deviceEngagementBytes)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Println(hex.EncodeToString(deviceEngagementBytes))
+		fmt.Println(diagnostic)
+	}
+}
@@ -1,10 +1,6 @@
 package main
 
 import (
-	"crypto"
-	"crypto/ecdsa"
-	"crypto/ed25519"
-	"crypto/elliptic"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
@@ -14,9 +10,9 @@ import (
 	"time"
 
 	"github.com/alex-richards/go-mdoc"
+	"github.com/alex-richards/go-mdoc/cipher_suite"
+	mdoccbor "github.com/alex-richards/go-mdoc/internal/cbor"
 	"github.com/alex-richards/go-mdoc/issuer"
-	"github.com/cloudflare/circl/sign/ed448"
-	"github.com/fxamacker/cbor/v2"
 	cli "github.com/jawher/mow.cli"
 	"github.com/veraison/go-cose"
 )
@@ -71,8 +67,13 @@ func cmdIssuerSignedCreate(cmd *cli.Cmd) {
 				log.Fatal(err)
 			}
 
+			signer, err := cipher_suite.NewSigner(privateKey)
+			if err != nil {
+				log.Fatal(err)
+			}
+
 			issuerAuthority = issuer.IssuerAuthority{
-				Signer:                    cryptoSigner{privateKey},
+				Signer:                    signer.Signer,
 				DocumentSignerCertificate: certificate,
 			}
 		}
@@ -90,7 +91,7 @@ func cmdIssuerSignedCreate(cmd *cli.Cmd) {
 				log.Fatal(err)
 			}
 
-			err = cbor.Unmarshal(deviceKeyData, &sdf)
+			err = mdoccbor.Unmarshal(deviceKeyData, &sdf)
 		}
 
 		inputItemPattern, err := regexp.Compile("^([a-z0-9.]+):([a-z0-9]+):([a-z0-9]+)(@(tstr|bstr|tdate|full-date|uint|bool))?$")
@@ -186,7 +187,7 @@ func cmdIssuerSignedCreate(cmd *cli.Cmd) {
 		}
 		issuerSigned.IssuerAuth = *issuerAuth
 
-		issuerSignedBytes, err := cbor.Marshal(issuerSigned)
+		issuerSignedBytes, err := mdoccbor.Marshal(issuerSigned)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -194,31 +195,3 @@ func cmdIssuerSignedCreate(cmd *cli.Cmd) {
 		println(hex.EncodeToString(issuerSignedBytes))
 	}
 }
-
-type cryptoSigner struct {
-	signer crypto.Signer
-}
-
-func (s cryptoSigner) Curve() mdoc.Curve {
-	switch privateKey := s.signer.(type) {
-	case *ecdsa.PrivateKey:
-		switch privateKey.Curve {
-		case elliptic.P256():
-			return mdoc.CurveP256
-		case elliptic.P384():
-			return mdoc.CurveP384
-		case elliptic.P521():
-			return mdoc.CurveP521
-		}
-	case ed25519.PrivateKey:
-		return mdoc.CurveEd25519
-	case ed448.PrivateKey:
-		return mdoc.CurveEd448
-	}
-
-	return ""
-}
-
-func (s cryptoSigner) Sign(rand io.Reader, message []byte) ([]byte, error) {
-	return s.signer.Sign(rand, message, nil)
-}
@@ -13,6 +13,7 @@ func main() {
 	app.Command("document-signer", "", cmdDocSigner)
 	app.Command("device-key", "", cmdDeviceKey)
 	app.Command("issuer-signed", "", cmdIssuerSigned)
+	app.Command("device-engagement", "", cmdDeviceEngagement)
 
 	_ = app.Run(os.Args)
 }
@@ -29,7 +29,7 @@ func coseAlgorithm(curve Curve) cose.Algorithm {
 		return cose.AlgorithmES256
 	case CurveP384, CurveBrainpoolP320r1, CurveBrainpoolP384r1:
 		return cose.AlgorithmES384
-	case CurveP521:
+	case CurveP521, CurveBrainpoolP512r1:
 		return cose.AlgorithmES512
 	case CurveEd448, CurveEd25519:
 		return cose.AlgorithmEdDSA
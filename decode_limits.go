@@ -0,0 +1,47 @@
+package mdoc
+
+import (
+	cbor2 "github.com/alex-richards/go-mdoc/internal/cbor"
+)
+
+var (
+	ErrMaxNestedLevels     = cbor2.ErrMaxNestedLevels
+	ErrMaxArrayElements    = cbor2.ErrMaxArrayElements
+	ErrMaxMapPairs         = cbor2.ErrMaxMapPairs
+	ErrMaxByteStringLength = cbor2.ErrMaxByteStringLength
+	ErrIndefiniteLength    = cbor2.ErrIndefiniteLength
+	ErrDuplicateMapKey     = cbor2.ErrDuplicateMapKey
+)
+
+const (
+	DefaultMaxNestedLevels     = cbor2.DefaultMaxNestedLevels
+	DefaultMaxArrayElements    = cbor2.DefaultMaxArrayElements
+	DefaultMaxMapPairs         = cbor2.DefaultMaxMapPairs
+	DefaultMaxByteStringLength = cbor2.DefaultMaxByteStringLength
+)
+
+// DecodeLimits bound the resources used decoding messages received from the other party:
+// the maximum nesting depth, array and map sizes and byte or text string length, and
+// whether duplicate map keys and indefinite length items are accepted. Zero valued limits
+// use the defaults, and CBOR embedded in tag 24 byte strings is checked as if nested in
+// place. The zero value rejects duplicate map keys and indefinite length items.
+type DecodeLimits cbor2.Limits
+
+// DefaultDecodeLimits returns the limits used when none are given.
+func DefaultDecodeLimits() *DecodeLimits {
+	return &DecodeLimits{
+		MaxNestedLevels:     DefaultMaxNestedLevels,
+		MaxArrayElements:    DefaultMaxArrayElements,
+		MaxMapPairs:         DefaultMaxMapPairs,
+		MaxByteStringLength: DefaultMaxByteStringLength,
+	}
+}
+
+// UnmarshalWithLimits decodes data into value, first checking it against limits, or the
+// default limits if nil.
+func UnmarshalWithLimits(data []byte, value any, limits *DecodeLimits) error {
+	if limits == nil {
+		limits = DefaultDecodeLimits()
+	}
+	return cbor2.UnmarshalWithLimits(data, value, cbor2.Limits(*limits))
+}
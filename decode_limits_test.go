@@ -0,0 +1,93 @@
+package mdoc
+
+import (
+	"errors"
+	"testing"
+
+	cbor2 "github.com/alex-richards/go-mdoc/internal/cbor"
+)
+
+func newDeviceRequestBytes(t testing.TB) []byte {
+	docRequest, err := NewDocRequest(&ItemsRequest{
+		DocType: "org.iso.18013.5.1.mDL",
+		NameSpaces: NameSpaces{
+			"org.iso.18013.5.1": {"family_name": false, "portrait": true},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := cbor2.Marshal(NewDeviceRequest([]DocRequest{*docRequest}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func newDeviceResponseBytes(t testing.TB) []byte {
+	data, err := cbor2.Marshal(&DeviceResponse{
+		Version:        DeviceResponseVersion,
+		DocumentErrors: []DocumentError{{"org.iso.18013.5.1.mDL": 0}},
+		Status:         StatusCodeOK,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func Test_DecodeDeviceRequest_Limits(t *testing.T) {
+	data := newDeviceRequestBytes(t)
+
+	tests := []struct {
+		name    string
+		limits  *DecodeLimits
+		wantErr error
+	}{
+		{name: "Default"},
+		{name: "MaxNestedLevels", limits: &DecodeLimits{MaxNestedLevels: 4}, wantErr: ErrMaxNestedLevels},
+		{name: "MaxByteStringLength", limits: &DecodeLimits{MaxByteStringLength: 8}, wantErr: ErrMaxByteStringLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deviceRequest, err := DecodeDeviceRequest(data, tt.limits)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && len(deviceRequest.DocRequests) != 1 {
+				t.Fatalf("expected 1 DocRequest, got %d", len(deviceRequest.DocRequests))
+			}
+		})
+	}
+}
+
+func FuzzDecodeDeviceRequest(f *testing.F) {
+	f.Add(newDeviceRequestBytes(f))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		deviceRequest, err := DecodeDeviceRequest(data, nil)
+		if err != nil {
+			return
+		}
+		for _, docRequest := range deviceRequest.DocRequests {
+			_, _ = docRequest.ItemsRequest()
+		}
+	})
+}
+
+func FuzzDecodeDeviceResponse(f *testing.F) {
+	f.Add(newDeviceResponseBytes(f))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		deviceResponse, err := DecodeDeviceResponse(data, nil)
+		if err != nil {
+			return
+		}
+		for _, document := range deviceResponse.Documents {
+			_, _ = document.IssuerSigned.NameSpaces.IssuerSignedItems()
+			_, _ = document.DeviceSigned.NameSpaces()
+		}
+	})
+}
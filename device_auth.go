@@ -1,18 +1,25 @@
 package mdoc
 
 import (
+	"crypto"
 	"errors"
+	"io"
 
 	cbor2 "github.com/alex-richards/go-mdoc/internal/cbor"
-	"github.com/fxamacker/cbor/v2"
+	cose2 "github.com/alex-richards/go-mdoc/internal/cose"
 	"github.com/veraison/go-cose"
+	"golang.org/x/crypto/hkdf"
 )
 
 var (
 	ErrNoDeviceAuthPresent        = errors.New("mdoc: no device auth present")
 	ErrMultipleDeviceAuthsPresent = errors.New("mdoc: multiple device auths present")
+	ErrMissingEReaderKey          = errors.New("mdoc: missing EReaderKey")
+)
 
-	ErrMACAuthNotSupported = errors.New("mdoc: MAC auth not supported")
+const (
+	eMacKeyLength = 32
+	eMacKeyInfo   = "EMacKey"
 )
 
 type DeviceAuth struct {
@@ -20,8 +27,12 @@ type DeviceAuth struct {
 	DeviceMAC       *DeviceMAC       `cbor:",omitempty"`
 }
 
+// Verify checks the DeviceSignature or DeviceMAC over deviceAuthenticationBytes.
+// eReaderKey is only required when verifying a DeviceMAC.
 func (da *DeviceAuth) Verify(
 	deviceKey *PublicKey,
+	eReaderKey *PrivateKey,
+	sessionTranscriptBytes *cbor2.TaggedEncodedCBOR,
 	deviceAuthenticationBytes *cbor2.TaggedEncodedCBOR,
 ) error {
 	switch {
@@ -32,7 +43,7 @@ func (da *DeviceAuth) Verify(
 		return verifyDeviceSignature(deviceKey, da.DeviceSignature, deviceAuthenticationBytes)
 
 	case da.DeviceMAC != nil:
-		return ErrMACAuthNotSupported
+		return verifyDeviceMAC(deviceKey, eReaderKey, sessionTranscriptBytes, da.DeviceMAC, deviceAuthenticationBytes)
 
 	default:
 		return ErrNoDeviceAuthPresent
@@ -44,7 +55,7 @@ func verifyDeviceSignature(
 	deviceSignature *DeviceSignature,
 	deviceAuthenticationBytes *cbor2.TaggedEncodedCBOR,
 ) error {
-	coseVerifier, err := (*cose.Key)(deviceKey).Verifier()
+	coseVerifier, err := deviceKey.verifier()
 	if err != nil {
 		return err
 	}
@@ -54,16 +65,78 @@ func verifyDeviceSignature(
 	return sign1.Verify([]byte{}, coseVerifier)
 }
 
+func verifyDeviceMAC(
+	deviceKey *PublicKey,
+	eReaderKey *PrivateKey,
+	sessionTranscriptBytes *cbor2.TaggedEncodedCBOR,
+	deviceMAC *DeviceMAC,
+	deviceAuthenticationBytes *cbor2.TaggedEncodedCBOR,
+) error {
+	if eReaderKey == nil || eReaderKey.Agreer == nil {
+		return ErrMissingEReaderKey
+	}
+
+	eMacKey, err := EMacKey(eReaderKey.Agreer, deviceKey, sessionTranscriptBytes.TaggedValue)
+	if err != nil {
+		return err
+	}
+
+	mac0 := (cose2.Mac0Message)(*deviceMAC)
+	mac0.Payload = deviceAuthenticationBytes.TaggedValue
+	return mac0.VerifyTag([]byte{}, eMacKey)
+}
+
+// EMacKey derives the key used for a DeviceMAC, from either the SDeviceKey and
+// EReaderKey public key, or the EReaderKey and SDeviceKey public key.
+func EMacKey(
+	agreer Agreer,
+	publicKey *PublicKey,
+	sessionTranscriptBytes []byte,
+) ([]byte, error) {
+	sharedSecret, err := agreer.Agree(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := crypto.SHA256.New()
+	_, err = salt.Write(sessionTranscriptBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	eMacKeySource := hkdf.New(
+		crypto.SHA256.New,
+		sharedSecret,
+		salt.Sum(nil),
+		[]byte(eMacKeyInfo),
+	)
+
+	eMacKey := make([]byte, eMacKeyLength)
+	_, err = io.ReadFull(eMacKeySource, eMacKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return eMacKey, nil
+}
+
 type DeviceSignature cose.UntaggedSign1Message
 
 func (ds *DeviceSignature) MarshalCBOR() ([]byte, error) {
-	return cbor.Marshal((*cose.UntaggedSign1Message)(ds))
+	return cbor2.Marshal((*cose.UntaggedSign1Message)(ds))
 }
 func (ds *DeviceSignature) UnmarshalCBOR(data []byte) error {
-	return cbor.Unmarshal(data, (*cose.UntaggedSign1Message)(ds))
+	return cbor2.Unmarshal(data, (*cose.UntaggedSign1Message)(ds))
 }
 
-type DeviceMAC any // TODO type DeviceMAC cose.MAC0Message
+type DeviceMAC cose2.Mac0Message
+
+func (dm *DeviceMAC) MarshalCBOR() ([]byte, error) {
+	return (*cose2.Mac0Message)(dm).MarshalCBOR()
+}
+func (dm *DeviceMAC) UnmarshalCBOR(data []byte) error {
+	return (*cose2.Mac0Message)(dm).UnmarshalCBOR(data)
+}
 
 type DeviceAuthentication struct {
 	_                    struct{} `cbor:",toarray"`
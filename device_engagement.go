@@ -9,18 +9,44 @@ import (
 
 var (
 	ErrUnrecognisedRetrievalMethod = errors.New("mdoc: unrecognized retrieval method")
+	ErrInvalidWifiPassPhrase       = errors.New("mdoc: invalid wifi pass phrase")
+	ErrInvalidWifiChannelInfo      = errors.New("mdoc: invalid wifi channel info")
+	ErrInvalidWifiBandInfo         = errors.New("mdoc: invalid wifi band info")
+	ErrInvalidCapability           = errors.New("mdoc: invalid capability")
+	ErrDeviceEngagementVersion     = errors.New("mdoc: device engagement version 1.1 required")
 )
 
 const (
 	DeviceEngagementVersion = "1.0"
+	// DeviceEngagementVersionCapabilities is required when OriginInfos or Capabilities are set.
+	DeviceEngagementVersionCapabilities = "1.1"
+)
+
+const (
+	deviceEngagementKeyVersion                = 0
+	deviceEngagementKeySecurity               = 1
+	deviceEngagementKeyDeviceRetrievalMethods = 2
+	deviceEngagementKeyServerRetrievalMethods = 3
+	deviceEngagementKeyProtocolInfo           = 4
+	deviceEngagementKeyOriginInfos            = 5
+	deviceEngagementKeyCapabilities           = 6
+
+	capabilitiesKeyHandoverSessionEstablishmentSupport = 2
+	capabilitiesKeyReaderAuthAllSupport                = 3
 )
 
 type DeviceEngagement struct {
-	Version                string                  `cbor:"0,keyasint"`
-	Security               Security                `cbor:"1,keyasint"`
-	DeviceRetrievalMethods []DeviceRetrievalMethod `cbor:"2,keyasint,omitempty"`
-	ServerRetrievalMethods []any                   `cbor:"3,keyasint,omitempty"` // TODO
-	ProtocolInfo           any                     `cbor:"4,keyasint,omitempty"` // TODO
+	Version                string
+	Security               Security
+	DeviceRetrievalMethods []DeviceRetrievalMethod
+	ServerRetrievalMethods *ServerRetrievalMethods
+	// ProtocolInfo is reserved for future use, and holds the encoded value as received.
+	ProtocolInfo []byte
+	OriginInfos  []OriginInfo
+	Capabilities *Capabilities
+	// Unknown holds the encoded values of unrecognised keys, so they are preserved when
+	// re-encoding an engagement from a newer version.
+	Unknown map[int][]byte
 }
 
 func NewDeviceEngagementBLE(
@@ -33,12 +59,12 @@ func NewDeviceEngagementBLE(
 	}
 
 	return &DeviceEngagement{
-		DeviceEngagementVersion,
-		Security{
+		Version: DeviceEngagementVersion,
+		Security: Security{
 			CipherSuiteIdentifier: CipherSuiteVersion,
 			EDeviceKeyBytes:       *eDeviceKeyBytes,
 		},
-		[]DeviceRetrievalMethod{
+		DeviceRetrievalMethods: []DeviceRetrievalMethod{
 			{
 				Type:    DeviceRetrievalMethodTypeBLE,
 				Version: DeviceRetrievalVersion,
@@ -50,11 +76,87 @@ func NewDeviceEngagementBLE(
 				},
 			},
 		},
-		nil,
-		nil,
 	}, nil
 }
 
+func (de *DeviceEngagement) EDeviceKey() (*PublicKey, error) {
+	eDeviceKey := new(PublicKey)
+	if err := eDeviceKey.UnmarshalCBOR(de.Security.EDeviceKeyBytes.UntaggedValue); err != nil {
+		return nil, err
+	}
+
+	return eDeviceKey, nil
+}
+
+type OriginInfoCategory uint
+
+const (
+	OriginInfoCategoryDelivery OriginInfoCategory = 0
+	OriginInfoCategoryReceive  OriginInfoCategory = 1
+)
+
+type OriginInfoType uint
+
+const (
+	OriginInfoTypeWebsite OriginInfoType = 1
+)
+
+type OriginInfo struct {
+	Category OriginInfoCategory `cbor:"cat"`
+	Type     OriginInfoType     `cbor:"type"`
+	Details  OriginInfoDetails  `cbor:"details"`
+}
+
+type OriginInfoDetails struct {
+	BaseURL string `cbor:"baseUrl,omitempty"`
+}
+
+// Capabilities are the optional features supported by the holder. Unknown holds the
+// encoded values of unrecognised capabilities.
+type Capabilities struct {
+	HandoverSessionEstablishmentSupport CapabilitySupport
+	ReaderAuthAllSupport                CapabilitySupport
+	Unknown                             map[int][]byte
+}
+
+// CapabilitySupport is the encoded value of a capability, true, false or null, kept as
+// received so that the DeviceEngagement, and so the SessionTranscript, re-encodes
+// unchanged. It is nil when the capability is absent.
+type CapabilitySupport []byte
+
+var (
+	CapabilitySupported    = CapabilitySupport{0xf5}
+	CapabilityNotSupported = CapabilitySupport{0xf4}
+)
+
+// Supported reports whether the capability is present with a value of true or null.
+func (cs CapabilitySupport) Supported() bool {
+	return len(cs) == 1 && (cs[0] == 0xf5 || cs[0] == 0xf6)
+}
+
+func newCapabilitySupport(data []byte) (CapabilitySupport, error) {
+	if len(data) != 1 || data[0] < 0xf4 || data[0] > 0xf6 {
+		return nil, ErrInvalidCapability
+	}
+	return CapabilitySupport{data[0]}, nil
+}
+
+// ServerRetrievalMethods are the server retrieval methods advertised in a DeviceEngagement.
+// OIDC is only encoded and decoded, the server package implements WebAPI retrieval alone.
+type ServerRetrievalMethods struct {
+	WebAPI *ServerRetrievalInformation `cbor:"webApi,omitempty"`
+	OIDC   *ServerRetrievalInformation `cbor:"oidc,omitempty"`
+}
+
+const ServerRetrievalVersion = 1
+
+type ServerRetrievalInformation struct {
+	_                    struct{} `cbor:",toarray"`
+	Version              uint
+	IssuerURL            string
+	ServerRetrievalToken string
+}
+
 type Security struct {
 	_                     struct{} `cbor:",toarray"`
 	CipherSuiteIdentifier int
@@ -79,6 +181,15 @@ type DeviceRetrievalMethod struct {
 
 type RetrievalOptions any
 
+// UnknownRetrievalOptions holds the encoded options of an unrecognised retrieval method,
+// so engagements advertising newer methods can still be decoded and re-encoded.
+type UnknownRetrievalOptions []byte
+
+const (
+	WifiPassPhraseMinLength = 8
+	WifiPassPhraseMaxLength = 63
+)
+
 type WifiOptions struct {
 	PassPhraseInfoPassPhrase  string `cbor:"0,keyasint,omitempty"`
 	ChannelInfoOperatingClass uint   `cbor:"1,keyasint,omitempty"`
@@ -86,6 +197,70 @@ type WifiOptions struct {
 	BandInfoSupportedBands    []byte `cbor:"3,keyasint,omitempty"`
 }
 
+// WifiBand is a Wi-Fi Aware band ID, the bit index in the supported bands bitmap.
+type WifiBand uint
+
+const (
+	WifiBandTVWhiteSpaces WifiBand = 0
+	WifiBandSub1GHz       WifiBand = 1
+	WifiBand2_4GHz        WifiBand = 2
+	WifiBand3_6GHz        WifiBand = 3
+	WifiBand4_9And5GHz    WifiBand = 4
+	WifiBand60GHz         WifiBand = 5
+	WifiBand45GHz         WifiBand = 6
+	WifiBand6GHz          WifiBand = 7
+)
+
+// NewWifiBandInfo encodes bands as a supported bands bitmap.
+func NewWifiBandInfo(bands ...WifiBand) []byte {
+	var bitmap []byte
+	for _, band := range bands {
+		for uint(len(bitmap)) <= uint(band)/8 {
+			bitmap = append(bitmap, 0)
+		}
+		bitmap[band/8] |= 1 << (band % 8)
+	}
+	return bitmap
+}
+
+// SupportedBands decodes the supported bands bitmap.
+func (wo *WifiOptions) SupportedBands() []WifiBand {
+	var bands []WifiBand
+	for i, b := range wo.BandInfoSupportedBands {
+		for bit := 0; bit < 8; bit++ {
+			if b&(1<<bit) != 0 {
+				bands = append(bands, WifiBand(i*8+bit))
+			}
+		}
+	}
+	return bands
+}
+
+// Validate checks the pass phrase is 8 to 63 printable ASCII characters, a channel number
+// is accompanied by its operating class, and band info contains at least one band.
+func (wo *WifiOptions) Validate() error {
+	if passPhrase := wo.PassPhraseInfoPassPhrase; passPhrase != "" {
+		if len(passPhrase) < WifiPassPhraseMinLength || len(passPhrase) > WifiPassPhraseMaxLength {
+			return ErrInvalidWifiPassPhrase
+		}
+		for i := 0; i < len(passPhrase); i++ {
+			if passPhrase[i] < 0x20 || passPhrase[i] > 0x7e {
+				return ErrInvalidWifiPassPhrase
+			}
+		}
+	}
+
+	if wo.ChannelInfoChannelNumber != 0 && wo.ChannelInfoOperatingClass == 0 {
+		return ErrInvalidWifiChannelInfo
+	}
+
+	if wo.BandInfoSupportedBands != nil && len(wo.SupportedBands()) == 0 {
+		return ErrInvalidWifiBandInfo
+	}
+
+	return nil
+}
+
 type BLEAddress [6]byte
 
 type BLEOptions struct {
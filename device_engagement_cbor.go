@@ -1,11 +1,148 @@
-//go:build !mdoc_tinycbor
-
 package mdoc
 
 import (
+	cbor2 "github.com/alex-richards/go-mdoc/internal/cbor"
 	"github.com/fxamacker/cbor/v2"
 )
 
+// MarshalCBOR encodes the DeviceEngagement, returning ErrDeviceEngagementVersion if
+// OriginInfos or Capabilities are set with version 1.0.
+func (de *DeviceEngagement) MarshalCBOR() ([]byte, error) {
+	if de.Version == DeviceEngagementVersion && (len(de.OriginInfos) > 0 || de.Capabilities != nil) {
+		return nil, ErrDeviceEngagementVersion
+	}
+
+	fields := make(map[int]cbor.RawMessage, len(de.Unknown)+7)
+	for key, value := range de.Unknown {
+		fields[key] = value
+	}
+
+	set := func(key int, value any) error {
+		valueBytes, err := cbor2.Marshal(value)
+		if err != nil {
+			return err
+		}
+		fields[key] = valueBytes
+		return nil
+	}
+
+	if err := set(deviceEngagementKeyVersion, de.Version); err != nil {
+		return nil, err
+	}
+	if err := set(deviceEngagementKeySecurity, &de.Security); err != nil {
+		return nil, err
+	}
+	if len(de.DeviceRetrievalMethods) > 0 {
+		if err := set(deviceEngagementKeyDeviceRetrievalMethods, de.DeviceRetrievalMethods); err != nil {
+			return nil, err
+		}
+	}
+	if de.ServerRetrievalMethods != nil {
+		if err := set(deviceEngagementKeyServerRetrievalMethods, de.ServerRetrievalMethods); err != nil {
+			return nil, err
+		}
+	}
+	if de.ProtocolInfo != nil {
+		fields[deviceEngagementKeyProtocolInfo] = de.ProtocolInfo
+	}
+	if len(de.OriginInfos) > 0 {
+		if err := set(deviceEngagementKeyOriginInfos, de.OriginInfos); err != nil {
+			return nil, err
+		}
+	}
+	if de.Capabilities != nil {
+		if err := set(deviceEngagementKeyCapabilities, de.Capabilities); err != nil {
+			return nil, err
+		}
+	}
+
+	return cbor2.Marshal(fields)
+}
+
+func (de *DeviceEngagement) UnmarshalCBOR(data []byte) error {
+	var fields map[int]cbor.RawMessage
+	if err := cbor2.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	var deviceEngagement DeviceEngagement
+	for key, value := range fields {
+		var err error
+		switch key {
+		case deviceEngagementKeyVersion:
+			err = cbor2.Unmarshal(value, &deviceEngagement.Version)
+		case deviceEngagementKeySecurity:
+			err = cbor2.Unmarshal(value, &deviceEngagement.Security)
+		case deviceEngagementKeyDeviceRetrievalMethods:
+			err = cbor2.Unmarshal(value, &deviceEngagement.DeviceRetrievalMethods)
+		case deviceEngagementKeyServerRetrievalMethods:
+			err = cbor2.Unmarshal(value, &deviceEngagement.ServerRetrievalMethods)
+		case deviceEngagementKeyProtocolInfo:
+			deviceEngagement.ProtocolInfo = value
+		case deviceEngagementKeyOriginInfos:
+			err = cbor2.Unmarshal(value, &deviceEngagement.OriginInfos)
+		case deviceEngagementKeyCapabilities:
+			err = cbor2.Unmarshal(value, &deviceEngagement.Capabilities)
+		default:
+			if deviceEngagement.Unknown == nil {
+				deviceEngagement.Unknown = make(map[int][]byte)
+			}
+			deviceEngagement.Unknown[key] = value
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	*de = deviceEngagement
+	return nil
+}
+
+func (c *Capabilities) MarshalCBOR() ([]byte, error) {
+	fields := make(map[int]cbor.RawMessage, len(c.Unknown)+2)
+	for key, value := range c.Unknown {
+		fields[key] = value
+	}
+
+	if c.HandoverSessionEstablishmentSupport != nil {
+		fields[capabilitiesKeyHandoverSessionEstablishmentSupport] = cbor.RawMessage(c.HandoverSessionEstablishmentSupport)
+	}
+	if c.ReaderAuthAllSupport != nil {
+		fields[capabilitiesKeyReaderAuthAllSupport] = cbor.RawMessage(c.ReaderAuthAllSupport)
+	}
+
+	return cbor2.Marshal(fields)
+}
+
+func (c *Capabilities) UnmarshalCBOR(data []byte) error {
+	var fields map[int]cbor.RawMessage
+	if err := cbor2.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	var capabilities Capabilities
+	for key, value := range fields {
+		var err error
+		switch key {
+		case capabilitiesKeyHandoverSessionEstablishmentSupport:
+			capabilities.HandoverSessionEstablishmentSupport, err = newCapabilitySupport(value)
+		case capabilitiesKeyReaderAuthAllSupport:
+			capabilities.ReaderAuthAllSupport, err = newCapabilitySupport(value)
+		default:
+			if capabilities.Unknown == nil {
+				capabilities.Unknown = make(map[int][]byte)
+			}
+			capabilities.Unknown[key] = value
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	*c = capabilities
+	return nil
+}
+
 type intermediateDeviceRetrievalMethod struct {
 	_                struct{} `cbor:",toarray"`
 	Type             DeviceRetrievalMethodType
@@ -13,38 +150,32 @@ type intermediateDeviceRetrievalMethod struct {
 	RetrievalOptions cbor.RawMessage
 }
 
-func (de *DeviceEngagement) EDeviceKey() (*PublicKey, error) {
-	eDeviceKey := new(PublicKey)
-	if err := cbor.Unmarshal(de.Security.EDeviceKeyBytes.UntaggedValue, eDeviceKey); err != nil {
-		return nil, err
-	}
-
-	return eDeviceKey, nil
-}
-
 func (drm *DeviceRetrievalMethod) MarshalCBOR() ([]byte, error) {
 	var err error
 
 	var retrievalOptionsBytes []byte
 	switch retrievalOptions := drm.RetrievalOptions.(type) {
 	case WifiOptions:
-		retrievalOptionsBytes, err = cbor.Marshal(&retrievalOptions)
+		retrievalOptionsBytes, err = cbor2.Marshal(&retrievalOptions)
 		if err != nil {
 			return nil, err
 		}
 
 	case BLEOptions:
-		retrievalOptionsBytes, err = cbor.Marshal(&retrievalOptions)
+		retrievalOptionsBytes, err = cbor2.Marshal(&retrievalOptions)
 		if err != nil {
 			return nil, err
 		}
 
 	case NFCOptions:
-		retrievalOptionsBytes, err = cbor.Marshal(&retrievalOptions)
+		retrievalOptionsBytes, err = cbor2.Marshal(&retrievalOptions)
 		if err != nil {
 			return nil, err
 		}
 
+	case UnknownRetrievalOptions:
+		retrievalOptionsBytes = retrievalOptions
+
 	default:
 		return nil, ErrUnrecognisedRetrievalMethod
 	}
@@ -54,14 +185,14 @@ func (drm *DeviceRetrievalMethod) MarshalCBOR() ([]byte, error) {
 		Version:          drm.Version,
 		RetrievalOptions: retrievalOptionsBytes,
 	}
-	return cbor.Marshal(&intermediateDeviceRetrievalMethod)
+	return cbor2.Marshal(&intermediateDeviceRetrievalMethod)
 }
 
 func (drm *DeviceRetrievalMethod) UnmarshalCBOR(data []byte) error {
 	var err error
 
 	var intermediateDeviceRetrievalMethod intermediateDeviceRetrievalMethod
-	if err = cbor.Unmarshal(data, &intermediateDeviceRetrievalMethod); err != nil {
+	if err = cbor2.Unmarshal(data, &intermediateDeviceRetrievalMethod); err != nil {
 		return err
 	}
 
@@ -69,27 +200,27 @@ func (drm *DeviceRetrievalMethod) UnmarshalCBOR(data []byte) error {
 	switch intermediateDeviceRetrievalMethod.Type {
 	case DeviceRetrievalMethodTypeWiFiAware:
 		var wifiOptions WifiOptions
-		if err = cbor.Unmarshal(intermediateDeviceRetrievalMethod.RetrievalOptions, &wifiOptions); err != nil {
+		if err = cbor2.Unmarshal(intermediateDeviceRetrievalMethod.RetrievalOptions, &wifiOptions); err != nil {
 			return err
 		}
 		retrievalOptions = wifiOptions
 
 	case DeviceRetrievalMethodTypeBLE:
 		var bleOptions BLEOptions
-		if err = cbor.Unmarshal(intermediateDeviceRetrievalMethod.RetrievalOptions, &bleOptions); err != nil {
+		if err = cbor2.Unmarshal(intermediateDeviceRetrievalMethod.RetrievalOptions, &bleOptions); err != nil {
 			return err
 		}
 		retrievalOptions = bleOptions
 
 	case DeviceRetrievalMethodTypeNFC:
 		var nfcOptions NFCOptions
-		if err = cbor.Unmarshal(intermediateDeviceRetrievalMethod.RetrievalOptions, &nfcOptions); err != nil {
+		if err = cbor2.Unmarshal(intermediateDeviceRetrievalMethod.RetrievalOptions, &nfcOptions); err != nil {
 			return err
 		}
 		retrievalOptions = nfcOptions
 
 	default:
-		return ErrUnrecognisedRetrievalMethod
+		retrievalOptions = UnknownRetrievalOptions(intermediateDeviceRetrievalMethod.RetrievalOptions)
 	}
 
 	drm.Type = intermediateDeviceRetrievalMethod.Type
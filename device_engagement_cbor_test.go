@@ -109,7 +109,11 @@ func Test_DeviceRetrievalMethod_UnmarshalCBOR(t *testing.T) {
 				Version:          1,
 				RetrievalOptions: WifiOptions{},
 			},
-			wantErr: "mdoc: unrecognized retrieval method",
+			want: &DeviceRetrievalMethod{
+				Type:             123,
+				Version:          1,
+				RetrievalOptions: UnknownRetrievalOptions{0xa0},
+			},
 		},
 	}
 
@@ -142,3 +146,104 @@ func Test_DeviceRetrievalMethod_UnmarshalCBOR(t *testing.T) {
 		})
 	}
 }
+
+func Test_ServerRetrievalMethods_CBOR_RoundTrip(t *testing.T) {
+	serverRetrievalMethods := &ServerRetrievalMethods{
+		WebAPI: &ServerRetrievalInformation{
+			Version:              ServerRetrievalVersion,
+			IssuerURL:            "https://issuer.example.com",
+			ServerRetrievalToken: "token",
+		},
+	}
+
+	data, err := cbor.Marshal(serverRetrievalMethods)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diagnostic, err := cbor.Diagnose(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"webApi": [1, "https://issuer.example.com", "token"]}`; diagnostic != want {
+		t.Fatalf("expected %s, got %s", want, diagnostic)
+	}
+
+	var got ServerRetrievalMethods
+	if err = cbor.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(serverRetrievalMethods, &got); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_DeviceEngagement_CBOR_PreservesUnknown(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	deviceEngagement, err := NewDeviceEngagementBLE(newX25519PublicKey(t, rand), nil, testutil.NewUUID(t, rand))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	security, err := cbor.Marshal(&deviceEngagement.Security)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deviceRetrievalMethods, err := cbor.Marshal(deviceEngagement.DeviceRetrievalMethods)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encodeMode, err := cbor.EncOptions{Sort: cbor.SortCanonical}.EncMode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := encodeMode.Marshal(map[int]any{
+		0:   DeviceEngagementVersionCapabilities,
+		1:   cbor.RawMessage(security),
+		2:   cbor.RawMessage(deviceRetrievalMethods),
+		4:   map[string]any{"future": []int{1, 2, 3}},
+		5:   []any{map[string]any{"cat": 1, "type": 1, "details": map[string]any{"baseUrl": "https://reader.example.com"}}},
+		6:   map[int]any{2: false, 3: nil, 99: "unknown capability"},
+		7:   "unknown",
+		100: map[string]any{"unknown": true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got DeviceEngagement
+	if err = cbor.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	wantOriginInfos := []OriginInfo{{
+		Category: OriginInfoCategoryReceive,
+		Type:     OriginInfoTypeWebsite,
+		Details:  OriginInfoDetails{BaseURL: "https://reader.example.com"},
+	}}
+	if diff := cmp.Diff(wantOriginInfos, got.OriginInfos); diff != "" {
+		t.Fatal(diff)
+	}
+	if got.Capabilities == nil ||
+		got.Capabilities.HandoverSessionEstablishmentSupport.Supported() ||
+		!got.Capabilities.ReaderAuthAllSupport.Supported() ||
+		len(got.Capabilities.Unknown) != 1 {
+		t.Fatalf("unexpected capabilities %+v", got.Capabilities)
+	}
+	if len(got.Unknown) != 2 {
+		t.Fatalf("expected 2 unknown keys, got %d", len(got.Unknown))
+	}
+
+	gotData, err := cbor.Marshal(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(data, gotData); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -0,0 +1,54 @@
+package mdoc
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	cbor2 "github.com/alex-richards/go-mdoc/internal/cbor"
+)
+
+var (
+	ErrInvalidDeviceEngagementURI = errors.New("mdoc: invalid device engagement URI")
+)
+
+const (
+	DeviceEngagementURIScheme = "mdoc:"
+)
+
+// NewDeviceEngagementURI encodes deviceEngagement as an "mdoc:" URI for QR engagement.
+func NewDeviceEngagementURI(deviceEngagement *DeviceEngagement) (string, error) {
+	deviceEngagementBytes, err := cbor2.Marshal(deviceEngagement)
+	if err != nil {
+		return "", err
+	}
+
+	return EncodeDeviceEngagementURI(deviceEngagementBytes), nil
+}
+
+// EncodeDeviceEngagementURI encodes already encoded DeviceEngagement bytes as an "mdoc:" URI.
+func EncodeDeviceEngagementURI(deviceEngagementBytes []byte) string {
+	return DeviceEngagementURIScheme + base64.RawURLEncoding.EncodeToString(deviceEngagementBytes)
+}
+
+// ParseDeviceEngagementURI decodes a scanned "mdoc:" URI, returning the DeviceEngagement
+// along with the exact bytes it was decoded from, as required for the SessionTranscript.
+func ParseDeviceEngagementURI(uri string) (*DeviceEngagement, []byte, error) {
+	if len(uri) < len(DeviceEngagementURIScheme) ||
+		!strings.EqualFold(uri[:len(DeviceEngagementURIScheme)], DeviceEngagementURIScheme) {
+		return nil, nil, ErrInvalidDeviceEngagementURI
+	}
+
+	encoded := strings.TrimRight(uri[len(DeviceEngagementURIScheme):], "=")
+	deviceEngagementBytes, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(deviceEngagementBytes) == 0 {
+		return nil, nil, ErrInvalidDeviceEngagementURI
+	}
+
+	deviceEngagement := new(DeviceEngagement)
+	if err = cbor2.Unmarshal(deviceEngagementBytes, deviceEngagement); err != nil {
+		return nil, nil, err
+	}
+
+	return deviceEngagement, deviceEngagementBytes, nil
+}
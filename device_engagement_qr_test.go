@@ -0,0 +1,129 @@
+package mdoc
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/alex-richards/go-mdoc/internal/testutil"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/veraison/go-cose"
+)
+
+func newX25519PublicKey(t testing.TB, rand io.Reader) *PublicKey {
+	t.Helper()
+
+	privateKey, err := ecdh.X25519().GenerateKey(rand)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &PublicKey{
+		Type: cose.KeyTypeOKP,
+		Params: map[any]any{
+			cose.KeyLabelOKPCurve: cose.CurveX25519,
+			cose.KeyLabelOKPX:     privateKey.PublicKey().Bytes(),
+		},
+	}
+}
+
+func Test_DeviceEngagementURI_RoundTrip(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	eDeviceKey := newX25519PublicKey(t, rand)
+	uuid := testutil.NewUUID(t, rand)
+
+	deviceEngagement, err := NewDeviceEngagementBLE(eDeviceKey, nil, uuid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uri, err := NewDeviceEngagementURI(deviceEngagement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(uri, DeviceEngagementURIScheme) || strings.ContainsAny(uri, "+/=") {
+		t.Fatalf("invalid uri %s", uri)
+	}
+
+	got, gotBytes, err := ParseDeviceEngagementURI(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(deviceEngagement, got); diff != "" {
+		t.Fatal(diff)
+	}
+
+	wantBytes, err := cbor.Marshal(deviceEngagement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(wantBytes, gotBytes) {
+		t.Fatal("bytes mismatch")
+	}
+}
+
+func Test_ParseDeviceEngagementURI_PreservesBytes(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	deviceEngagement, err := NewDeviceEngagementBLE(newX25519PublicKey(t, rand), testutil.NewUUID(t, rand), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	security, err := cbor.Marshal(deviceEngagement.Security)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deviceRetrievalMethods, err := cbor.Marshal(deviceEngagement.DeviceRetrievalMethods)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// keys out of canonical order
+	deviceEngagementBytes := []byte{0xa3, 0x02}
+	deviceEngagementBytes = append(deviceEngagementBytes, deviceRetrievalMethods...)
+	deviceEngagementBytes = append(deviceEngagementBytes, 0x01)
+	deviceEngagementBytes = append(deviceEngagementBytes, security...)
+	deviceEngagementBytes = append(deviceEngagementBytes, 0x00, 0x63, '1', '.', '0')
+
+	got, gotBytes, err := ParseDeviceEngagementURI("MDOC:" + EncodeDeviceEngagementURI(deviceEngagementBytes)[len(DeviceEngagementURIScheme):])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(deviceEngagement, got); diff != "" {
+		t.Fatal(diff)
+	}
+	if !bytes.Equal(deviceEngagementBytes, gotBytes) {
+		t.Fatal("original bytes not preserved")
+	}
+}
+
+func Test_ParseDeviceEngagementURI_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		uri  string
+	}{
+		{name: "Empty", uri: ""},
+		{name: "Wrong scheme", uri: "https://example.com"},
+		{name: "No data", uri: "mdoc:"},
+		{name: "Invalid base64", uri: "mdoc:!!!!"},
+		{name: "Standard base64", uri: "mdoc:o+/="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ParseDeviceEngagementURI(tt.uri); !errors.Is(err, ErrInvalidDeviceEngagementURI) {
+				t.Fatalf("expected %v, got %v", ErrInvalidDeviceEngagementURI, err)
+			}
+		})
+	}
+}
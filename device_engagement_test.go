@@ -0,0 +1,311 @@
+package mdoc
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	cbor2 "github.com/alex-richards/go-mdoc/internal/cbor"
+	"github.com/alex-richards/go-mdoc/util"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_WifiBandInfo_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		bands []WifiBand
+		want  []byte
+	}{
+		{name: "None", bands: nil, want: nil},
+		{name: "2.4GHz", bands: []WifiBand{WifiBand2_4GHz}, want: []byte{0x04}},
+		{name: "2.4GHz and 5GHz", bands: []WifiBand{WifiBand2_4GHz, WifiBand4_9And5GHz}, want: []byte{0x14}},
+		{name: "All", bands: []WifiBand{0, 1, 2, 3, 4, 5, 6, 7}, want: []byte{0xff}},
+		{name: "Future band", bands: []WifiBand{WifiBand6GHz, 9}, want: []byte{0x80, 0x02}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bandInfo := NewWifiBandInfo(tt.bands...)
+			if diff := cmp.Diff(tt.want, bandInfo); diff != "" {
+				t.Fatal(diff)
+			}
+
+			wifiOptions := WifiOptions{BandInfoSupportedBands: bandInfo}
+			if diff := cmp.Diff(tt.bands, wifiOptions.SupportedBands()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_WifiOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		wifiOptions WifiOptions
+		want        error
+	}{
+		{name: "Empty", wifiOptions: WifiOptions{}},
+		{
+			name: "Valid",
+			wifiOptions: WifiOptions{
+				PassPhraseInfoPassPhrase:  "passphrase",
+				ChannelInfoOperatingClass: 81,
+				ChannelInfoChannelNumber:  6,
+				BandInfoSupportedBands:    NewWifiBandInfo(WifiBand2_4GHz),
+			},
+		},
+		{name: "Pass phrase too short", wifiOptions: WifiOptions{PassPhraseInfoPassPhrase: "short"}, want: ErrInvalidWifiPassPhrase},
+		{name: "Pass phrase too long", wifiOptions: WifiOptions{PassPhraseInfoPassPhrase: strings.Repeat("a", 64)}, want: ErrInvalidWifiPassPhrase},
+		{name: "Pass phrase not printable", wifiOptions: WifiOptions{PassPhraseInfoPassPhrase: "pass\nphrase"}, want: ErrInvalidWifiPassPhrase},
+		{name: "Channel without operating class", wifiOptions: WifiOptions{ChannelInfoChannelNumber: 6}, want: ErrInvalidWifiChannelInfo},
+		{name: "No bands", wifiOptions: WifiOptions{BandInfoSupportedBands: []byte{0x00}}, want: ErrInvalidWifiBandInfo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.wifiOptions.Validate(); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func Test_DeviceRetrievalMethod_Unknown_RoundTrip(t *testing.T) {
+	data, err := cbor.Marshal([]any{99, 1, map[string]any{"future": []int{1, 2}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var deviceRetrievalMethod DeviceRetrievalMethod
+	if err = cbor.Unmarshal(data, &deviceRetrievalMethod); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := deviceRetrievalMethod.RetrievalOptions.(UnknownRetrievalOptions); !ok {
+		t.Fatalf("expected UnknownRetrievalOptions, got %T", deviceRetrievalMethod.RetrievalOptions)
+	}
+
+	got, err := cbor.Marshal(&deviceRetrievalMethod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(data, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_Capabilities_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		wantHandover  bool
+		wantReaderAll bool
+		wantErr       error
+	}{
+		{name: "True", data: "a2" + "02f5" + "03f5", wantHandover: true, wantReaderAll: true},
+		{name: "False", data: "a2" + "02f4" + "03f4", wantHandover: false, wantReaderAll: false},
+		{name: "Null", data: "a2" + "02f6" + "03f6", wantHandover: true, wantReaderAll: true},
+		{name: "Mixed", data: "a2" + "02f4" + "03f6", wantHandover: false, wantReaderAll: true},
+		{name: "Absent", data: "a0", wantHandover: false, wantReaderAll: false},
+		{name: "Invalid", data: "a1" + "0201", wantErr: ErrInvalidCapability},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var capabilities Capabilities
+			err = cbor2.Unmarshal(data, &capabilities)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if supported := capabilities.HandoverSessionEstablishmentSupport.Supported(); supported != tt.wantHandover {
+				t.Fatalf("expected %v, got %v", tt.wantHandover, supported)
+			}
+			if supported := capabilities.ReaderAuthAllSupport.Supported(); supported != tt.wantReaderAll {
+				t.Fatalf("expected %v, got %v", tt.wantReaderAll, supported)
+			}
+
+			got, err := cbor2.Marshal(&capabilities)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(data, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_DeviceEngagement_Encoding(t *testing.T) {
+	eDeviceKeyBytes, err := cbor2.NewTaggedEncodedCBOR([]byte{0xa1, 0x01, 0x02})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uuid := util.UUID{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	address := BLEAddress{1, 2, 3, 4, 5, 6}
+
+	deviceEngagement := &DeviceEngagement{
+		Version: DeviceEngagementVersionCapabilities,
+		Security: Security{
+			CipherSuiteIdentifier: CipherSuiteVersion,
+			EDeviceKeyBytes:       *eDeviceKeyBytes,
+		},
+		DeviceRetrievalMethods: []DeviceRetrievalMethod{
+			{
+				Type:    DeviceRetrievalMethodTypeBLE,
+				Version: DeviceRetrievalVersion,
+				RetrievalOptions: BLEOptions{
+					SupportsPeripheralServer:      true,
+					PeripheralServerUUID:          &uuid,
+					PeripheralServerDeviceAddress: &address,
+				},
+			},
+			{
+				Type:    DeviceRetrievalMethodTypeNFC,
+				Version: DeviceRetrievalVersion,
+				RetrievalOptions: NFCOptions{
+					MaxLengthCommandData:  255,
+					MaxLengthResponseData: 65536,
+				},
+			},
+			{
+				Type:    DeviceRetrievalMethodTypeWiFiAware,
+				Version: DeviceRetrievalVersion,
+				RetrievalOptions: WifiOptions{
+					PassPhraseInfoPassPhrase:  "passphrase",
+					ChannelInfoOperatingClass: 81,
+					ChannelInfoChannelNumber:  6,
+					BandInfoSupportedBands:    NewWifiBandInfo(WifiBand2_4GHz),
+				},
+			},
+		},
+		ServerRetrievalMethods: &ServerRetrievalMethods{
+			WebAPI: &ServerRetrievalInformation{
+				Version:              ServerRetrievalVersion,
+				IssuerURL:            "https://example.com",
+				ServerRetrievalToken: "token",
+			},
+		},
+		OriginInfos: []OriginInfo{
+			{
+				Category: OriginInfoCategoryReceive,
+				Type:     OriginInfoTypeWebsite,
+				Details:  OriginInfoDetails{BaseURL: "https://example.com"},
+			},
+		},
+		Capabilities: &Capabilities{
+			ReaderAuthAllSupport: CapabilitySupported,
+			Unknown:              map[int][]byte{-1: {0xf6}},
+		},
+		Unknown: map[int][]byte{
+			-2: {0x01},
+			24: {0x02},
+		},
+	}
+
+	want, err := hex.DecodeString(
+		"a8" +
+			"00" + "63312e31" +
+			"01" + "8201d818" + "43a10102" +
+			"02" + "83" +
+			"8302" + "01" + "a4" + "00f5" + "01f4" + "0a50000102030405060708090a0b0c0d0e0f" + "1446010203040506" +
+			"8301" + "01" + "a2" + "0018ff" + "011a00010000" +
+			"8303" + "01" + "a4" + "006a70617373706872617365" + "011851" + "0206" + "034104" +
+			"03" + "a1" + "66776562417069" + "8301" + "7368747470733a2f2f6578616d706c652e636f6d" + "65746f6b656e" +
+			"05" + "81" + "a3" +
+			"63636174" + "01" +
+			"6474797065" + "01" +
+			"6764657461696c73" + "a1" + "676261736555726c" + "7368747470733a2f2f6578616d706c652e636f6d" +
+			"06" + "a2" + "03f5" + "20f6" +
+			"1818" + "02" +
+			"21" + "01",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := cbor2.Marshal(deviceEngagement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(hex.EncodeToString(want), hex.EncodeToString(got)); diff != "" {
+		t.Fatal(diff)
+	}
+
+	decoded := new(DeviceEngagement)
+	if err = cbor2.Unmarshal(got, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(deviceEngagement, decoded); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_DeviceEngagement_MarshalCBOR_Version(t *testing.T) {
+	eDeviceKeyBytes, err := cbor2.NewTaggedEncodedCBOR([]byte{0xa1, 0x01, 0x02})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name             string
+		deviceEngagement DeviceEngagement
+		wantErr          error
+	}{
+		{
+			name:             "1.0",
+			deviceEngagement: DeviceEngagement{Version: DeviceEngagementVersion},
+		},
+		{
+			name: "1.0 OriginInfos",
+			deviceEngagement: DeviceEngagement{
+				Version: DeviceEngagementVersion,
+				OriginInfos: []OriginInfo{{
+					Category: OriginInfoCategoryReceive,
+					Type:     OriginInfoTypeWebsite,
+					Details:  OriginInfoDetails{BaseURL: "https://example.com"},
+				}},
+			},
+			wantErr: ErrDeviceEngagementVersion,
+		},
+		{
+			name: "1.0 Capabilities",
+			deviceEngagement: DeviceEngagement{
+				Version:      DeviceEngagementVersion,
+				Capabilities: &Capabilities{ReaderAuthAllSupport: CapabilitySupported},
+			},
+			wantErr: ErrDeviceEngagementVersion,
+		},
+		{
+			name: "1.1 Capabilities",
+			deviceEngagement: DeviceEngagement{
+				Version:      DeviceEngagementVersionCapabilities,
+				Capabilities: &Capabilities{ReaderAuthAllSupport: CapabilitySupported},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.deviceEngagement.Security = Security{
+				CipherSuiteIdentifier: CipherSuiteVersion,
+				EDeviceKeyBytes:       *eDeviceKeyBytes,
+			}
+
+			if _, err := cbor2.Marshal(&tt.deviceEngagement); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	cbor2 "github.com/alex-richards/go-mdoc/internal/cbor"
-	"github.com/fxamacker/cbor/v2"
 )
 
 var (
@@ -30,6 +29,16 @@ func NewDeviceRequest(docRequests []DocRequest) *DeviceRequest {
 	}
 }
 
+// DecodeDeviceRequest decodes a DeviceRequest received from a reader, rejecting data
+// exceeding limits, or the default limits if nil.
+func DecodeDeviceRequest(data []byte, limits *DecodeLimits) (*DeviceRequest, error) {
+	deviceRequest := new(DeviceRequest)
+	if err := UnmarshalWithLimits(data, deviceRequest, limits); err != nil {
+		return nil, err
+	}
+	return deviceRequest, nil
+}
+
 func (dr *DeviceRequest) Verify(
 	rootCertificates []*x509.Certificate,
 	now time.Time,
@@ -39,15 +48,62 @@ func (dr *DeviceRequest) Verify(
 		return ErrDeviceRequestUnsupportedVersion
 	}
 
-	var err error
+	report, err := dr.VerifyReport(DefaultVerifierPolicy(), rootCertificates, now, sessionTranscript)
+	if err != nil {
+		return err
+	}
+
+	return report.Err()
+}
+
+// VerifyReport verifies the ReaderAuth of every DocRequest, continuing past failures and
+// recording each result in the returned DeviceRequestVerificationReport. A nil policy is
+// DefaultVerifierPolicy.
+func (dr *DeviceRequest) VerifyReport(
+	policy *VerifierPolicy,
+	rootCertificates []*x509.Certificate,
+	now time.Time,
+	sessionTranscript *SessionTranscript,
+) (*DeviceRequestVerificationReport, error) {
+	if dr.Version != DeviceRequestVersion {
+		return nil, ErrDeviceRequestUnsupportedVersion
+	}
+
+	policy = policy.orDefault()
+
+	report := &DeviceRequestVerificationReport{
+		ReaderAuths: make([]error, len(dr.DocRequests)),
+	}
+	for i, docRequest := range dr.DocRequests {
+		report.ReaderAuths[i] = docRequest.VerifyWithPolicy(policy, rootCertificates, now, sessionTranscript)
+	}
+
+	return report, nil
+}
+
+func (dr *DeviceRequest) requestedNameSpaces() (map[DocType]NameSpaces, error) {
+	requestedNameSpaces := make(map[DocType]NameSpaces, len(dr.DocRequests))
 	for _, docRequest := range dr.DocRequests {
-		err = docRequest.Verify(rootCertificates, now, sessionTranscript)
+		itemsRequest, err := docRequest.ItemsRequest()
 		if err != nil {
-			return err
+			return nil, err
 		}
-	}
 
-	return nil
+		nameSpaces, ok := requestedNameSpaces[itemsRequest.DocType]
+		if !ok {
+			nameSpaces = make(NameSpaces)
+			requestedNameSpaces[itemsRequest.DocType] = nameSpaces
+		}
+		for nameSpace, dataElements := range itemsRequest.NameSpaces {
+			if _, ok := nameSpaces[nameSpace]; !ok {
+				nameSpaces[nameSpace] = make(DataElements)
+			}
+			for dataElementIdentifier, intentToRetain := range dataElements {
+				nameSpaces[nameSpace][dataElementIdentifier] = intentToRetain
+			}
+		}
+	}
+	return requestedNameSpaces, nil
 }
 
 type DocRequest struct {
@@ -70,7 +126,7 @@ func NewDocRequest(itemsRequest *ItemsRequest) (*DocRequest, error) {
 func (dr DocRequest) ItemsRequest() (*ItemsRequest, error) {
 	var itemsRequest ItemsRequest
 
-	err := cbor.Unmarshal(dr.ItemsRequestBytes.UntaggedValue, &itemsRequest)
+	err := cbor2.Unmarshal(dr.ItemsRequestBytes.UntaggedValue, &itemsRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -102,10 +158,35 @@ func (dr DocRequest) Verify(
 	)
 }
 
+// VerifyWithPolicy verifies the ReaderAuth, if present, against policy. A DocRequest
+// without ReaderAuth is accepted unless the policy requires reader authentication. A nil
+// policy is DefaultVerifierPolicy.
+func (dr DocRequest) VerifyWithPolicy(
+	policy *VerifierPolicy,
+	rootCertificates []*x509.Certificate,
+	now time.Time,
+	sessionTranscript *SessionTranscript,
+) error {
+	policy = policy.orDefault()
+
+	if dr.ReaderAuth == nil {
+		if policy.RequireReaderAuth {
+			return ErrMissingReaderAuth
+		}
+		return nil
+	}
+
+	if err := policy.checkAlgorithm(&dr.ReaderAuth.Headers); err != nil {
+		return err
+	}
+
+	return dr.Verify(rootCertificates, now, sessionTranscript)
+}
+
 type ItemsRequest struct {
 	DocType     DocType        `cbor:"docType"`
 	NameSpaces  NameSpaces     `cbor:"nameSpaces"`
-	RequestInfo map[string]any `cbor:"requestInfo"`
+	RequestInfo map[string]any `cbor:"requestInfo,omitempty"`
 }
 
 type NameSpaces map[NameSpace]DataElements
@@ -1 +1,33 @@
 package mdoc
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_DocRequest_VerifyWithPolicy_MissingReaderAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *VerifierPolicy
+		wantErr error
+	}{
+		{name: "Required", policy: &VerifierPolicy{RequireReaderAuth: true}, wantErr: ErrMissingReaderAuth},
+		{name: "Optional", policy: &VerifierPolicy{}},
+		{name: "Nil Policy", wantErr: ErrMissingReaderAuth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docRequest, err := NewDocRequest(&ItemsRequest{DocType: "docType1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = docRequest.VerifyWithPolicy(tt.policy, nil, time.UnixMilli(1500), nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 var (
 	ErrMissingDigest               = errors.New("mdoc: missing digest")
 	ErrInvalidDigest               = errors.New("mdoc: incorrect digest")
+	ErrDuplicateDataElement        = errors.New("mdoc: duplicate data element")
 	ErrUnauthorizedDeviceNameSpace = errors.New("mdoc: unauthorized device name space")
 )
 
@@ -24,6 +25,20 @@ type DeviceResponse struct {
 	Status         StatusCode      `cbor:"status"`
 }
 
+const (
+	DeviceResponseVersion = "1.0"
+)
+
+// DecodeDeviceResponse decodes a DeviceResponse received from a holder, rejecting data
+// exceeding limits, or the default limits if nil.
+func DecodeDeviceResponse(data []byte, limits *DecodeLimits) (*DeviceResponse, error) {
+	deviceResponse := new(DeviceResponse)
+	if err := UnmarshalWithLimits(data, deviceResponse, limits); err != nil {
+		return nil, err
+	}
+	return deviceResponse, nil
+}
+
 type StatusCode uint
 
 const (
@@ -39,7 +54,7 @@ func NewDeviceResponse(
 	status StatusCode,
 ) *DeviceResponse {
 	return &DeviceResponse{
-		"1.0",
+		DeviceResponseVersion,
 		documents,
 		documentErrors,
 		status,
@@ -58,19 +73,62 @@ type Document struct {
 func (d *Document) Verify(
 	rootCertificates []*x509.Certificate,
 	now time.Time,
+	eReaderKey *PrivateKey,
 	sessionTranscript *SessionTranscript,
 ) error {
-	mobileSecurityObject, err := d.IssuerSigned.Verify(rootCertificates, now)
+	report, err := d.VerifyReport(DefaultVerifierPolicy(), rootCertificates, now, eReaderKey, sessionTranscript)
 	if err != nil {
 		return err
 	}
 
+	return report.Err()
+}
+
+// VerifyReport verifies the document, continuing past failed checks and recording each
+// result in the returned VerificationReport. An error is only returned when the document
+// is malformed and verification can't continue. A nil policy is DefaultVerifierPolicy.
+func (d *Document) VerifyReport(
+	policy *VerifierPolicy,
+	rootCertificates []*x509.Certificate,
+	now time.Time,
+	eReaderKey *PrivateKey,
+	sessionTranscript *SessionTranscript,
+) (*VerificationReport, error) {
+	policy = policy.orDefault()
+	report := newVerificationReport(d.DocType)
+
+	mobileSecurityObject, err := d.IssuerSigned.verify(policy, rootCertificates, now, report)
+	if err != nil {
+		return nil, err
+	}
+
+	if report.MobileSecurityObject == nil && mobileSecurityObject.DocType != d.DocType {
+		report.MobileSecurityObject = ErrDocTypeMismatch
+	}
+
+	sessionTranscriptBytes, err := NewSessionTranscriptBytes(sessionTranscript)
+	if err != nil {
+		return nil, err
+	}
+
 	deviceAuthenticationBytes, err := NewDeviceAuthenticationBytes(sessionTranscript, d.DocType, &d.DeviceSigned.NameSpacesBytes)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return d.DeviceSigned.Verify(&mobileSecurityObject.DeviceKeyInfo.DeviceKey, deviceAuthenticationBytes, mobileSecurityObject)
+	if err = d.DeviceSigned.verify(
+		&mobileSecurityObject.DeviceKeyInfo.DeviceKey,
+		eReaderKey,
+		sessionTranscriptBytes,
+		deviceAuthenticationBytes,
+		mobileSecurityObject,
+		policy,
+		report,
+	); err != nil {
+		return nil, err
+	}
+
+	return report, nil
 }
 
 type IssuerSigned struct {
@@ -79,36 +137,84 @@ type IssuerSigned struct {
 }
 
 func (is IssuerSigned) Verify(rootCertificates []*x509.Certificate, now time.Time) (*MobileSecurityObject, error) {
-	err := is.IssuerAuth.Verify(rootCertificates, now)
+	mobileSecurityObject, report, err := is.VerifyReport(DefaultVerifierPolicy(), rootCertificates, now)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = report.Err(); err != nil {
+		return nil, err
+	}
+
+	return mobileSecurityObject, nil
+}
+
+// VerifyReport verifies the issuer signed part of a document, recording the issuer auth,
+// validity and digest results in the returned VerificationReport. A nil policy is
+// DefaultVerifierPolicy.
+func (is IssuerSigned) VerifyReport(policy *VerifierPolicy, rootCertificates []*x509.Certificate, now time.Time) (*MobileSecurityObject, *VerificationReport, error) {
+	policy = policy.orDefault()
+	report := newVerificationReport("")
+
+	mobileSecurityObject, err := is.verify(policy, rootCertificates, now, report)
+	if err != nil {
+		return nil, nil, err
+	}
+	report.DocType = mobileSecurityObject.DocType
+
+	return mobileSecurityObject, report, nil
+}
+
+func (is IssuerSigned) verify(policy *VerifierPolicy, rootCertificates []*x509.Certificate, now time.Time, report *VerificationReport) (*MobileSecurityObject, error) {
+	report.IssuerAuth = is.IssuerAuth.Verify(rootCertificates, now)
+	report.violatePolicy(policy.checkAlgorithm(&is.IssuerAuth.Headers))
+
 	mobileSecurityObject, err := is.IssuerAuth.MobileSecurityObject()
 	if err != nil {
 		return nil, err
 	}
 
+	report.MobileSecurityObject = mobileSecurityObject.Verify()
+	report.violatePolicy(policy.checkDocType(mobileSecurityObject.DocType))
+	report.violatePolicy(policy.checkDigestAlgorithm(mobileSecurityObject.DigestAlgorithm))
+	report.violatePolicy(policy.checkCurve(&mobileSecurityObject.DeviceKeyInfo.DeviceKey))
+
+	report.Validity = mobileSecurityObject.ValidityInfo.Verify(now, policy.ClockSkew)
+	if mobileSecurityObject.ValidityInfo.UpdateExpected(now) {
+		report.Warnings = append(report.Warnings, ErrExpectedUpdatePassed)
+	}
+
 	hash, err := mobileSecurityObject.DigestAlgorithm.Hash()
 	if err != nil {
 		return nil, err
 	}
 
 	for nameSpace, issuerSignedItemBytess := range is.NameSpaces {
-		nameSpaceDigests, ok := mobileSecurityObject.ValueDigests[nameSpace]
-		if !ok {
-			return nil, ErrMissingDigest
-		}
+		nameSpaceDigests, nameSpaceOk := mobileSecurityObject.ValueDigests[nameSpace]
 
+		dataElementIdentifiers := make(map[DataElementIdentifier]struct{}, len(issuerSignedItemBytess))
+		digestIDs := make(map[DigestID]struct{}, len(issuerSignedItemBytess))
 		for _, issuerSignedItemBytes := range issuerSignedItemBytess {
 			issuerSignedItem, err := issuerSignedItemBytes.IssuerSignedItem()
 			if err != nil {
 				return nil, err
 			}
 
+			// a repeated element could otherwise hide a failed digest behind a valid one
+			if _, ok := dataElementIdentifiers[issuerSignedItem.ElementIdentifier]; ok {
+				return nil, ErrDuplicateDataElement
+			}
+			dataElementIdentifiers[issuerSignedItem.ElementIdentifier] = struct{}{}
+
+			if _, ok := digestIDs[issuerSignedItem.DigestID]; ok {
+				return nil, ErrDuplicateDigestID
+			}
+			digestIDs[issuerSignedItem.DigestID] = struct{}{}
+
 			expectedDigest, ok := nameSpaceDigests[issuerSignedItem.DigestID]
-			if !ok {
-				return nil, ErrMissingDigest
+			if !nameSpaceOk || !ok {
+				report.Digests.set(nameSpace, issuerSignedItem.ElementIdentifier, ErrMissingDigest)
+				continue
 			}
 
 			hash.Reset()
@@ -116,7 +222,18 @@ func (is IssuerSigned) Verify(rootCertificates []*x509.Certificate, now time.Tim
 			calculatedDigest := hash.Sum(nil)
 
 			if !bytes.Equal(calculatedDigest, expectedDigest) {
-				return nil, ErrInvalidDigest
+				report.Digests.set(nameSpace, issuerSignedItem.ElementIdentifier, ErrInvalidDigest)
+				continue
+			}
+
+			report.Digests.set(nameSpace, issuerSignedItem.ElementIdentifier, nil)
+
+			if policy.Schema != nil {
+				_, elementValue, err := issuerSignedItemBytes.RawElementValue()
+				if err != nil {
+					return nil, err
+				}
+				report.Types.set(nameSpace, issuerSignedItem.ElementIdentifier, policy.Schema.ValidateCBOR(nameSpace, issuerSignedItem.ElementIdentifier, elementValue))
 			}
 		}
 	}
@@ -200,10 +317,25 @@ func (isib *IssuerSignedItemBytes) UnmarshalCBOR(data []byte) error {
 
 func (isib *IssuerSignedItemBytes) IssuerSignedItem() (*IssuerSignedItem, error) {
 	issuerSignedItem := new(IssuerSignedItem)
-	err := cbor.Unmarshal(isib.UntaggedValue, &issuerSignedItem)
+	err := cbor2.Unmarshal(isib.UntaggedValue, &issuerSignedItem)
 	return issuerSignedItem, err
 }
 
+type rawIssuerSignedItem struct {
+	ElementIdentifier DataElementIdentifier `cbor:"elementIdentifier"`
+	ElementValue      cbor.RawMessage       `cbor:"elementValue"`
+}
+
+// RawElementValue returns the data element identifier and the encoded value, for decoding
+// into a specific type.
+func (isib *IssuerSignedItemBytes) RawElementValue() (DataElementIdentifier, []byte, error) {
+	var item rawIssuerSignedItem
+	if err := cbor2.Unmarshal(isib.UntaggedValue, &item); err != nil {
+		return "", nil, err
+	}
+	return item.ElementIdentifier, item.ElementValue, nil
+}
+
 type IssuerSignedItems map[NameSpace][]IssuerSignedItem
 
 func (isi IssuerSignedItems) Contains(nameSpace NameSpace, dataElementIdentifier DataElementIdentifier) bool {
@@ -232,25 +364,51 @@ type DeviceSigned struct {
 
 func (ds *DeviceSigned) Verify(
 	deviceKey *PublicKey,
+	eReaderKey *PrivateKey,
+	sessionTranscriptBytes *cbor2.TaggedEncodedCBOR,
 	deviceAuthenticationBytes *cbor2.TaggedEncodedCBOR,
 	mobileSecurityObject *MobileSecurityObject,
 ) error {
-	err := ds.DeviceAuth.Verify(deviceKey, deviceAuthenticationBytes)
+	report := newVerificationReport(mobileSecurityObject.DocType)
+
+	err := ds.verify(deviceKey, eReaderKey, sessionTranscriptBytes, deviceAuthenticationBytes, mobileSecurityObject, DefaultVerifierPolicy(), report)
 	if err != nil {
 		return err
 	}
 
+	return report.Err()
+}
+
+func (ds *DeviceSigned) verify(
+	deviceKey *PublicKey,
+	eReaderKey *PrivateKey,
+	sessionTranscriptBytes *cbor2.TaggedEncodedCBOR,
+	deviceAuthenticationBytes *cbor2.TaggedEncodedCBOR,
+	mobileSecurityObject *MobileSecurityObject,
+	policy *VerifierPolicy,
+	report *VerificationReport,
+) error {
+	report.DeviceAuth = ds.DeviceAuth.Verify(deviceKey, eReaderKey, sessionTranscriptBytes, deviceAuthenticationBytes)
+	if ds.DeviceAuth.DeviceSignature != nil {
+		report.violatePolicy(policy.checkAlgorithm(&ds.DeviceAuth.DeviceSignature.Headers))
+	}
+
 	deviceNameSpaces, err := ds.NameSpaces()
 	if err != nil {
 		return err
 	}
 
-	return deviceNameSpaces.Verify(mobileSecurityObject)
+	if policy.RejectDeviceSigned && len(deviceNameSpaces) > 0 {
+		report.violatePolicy(ErrDeviceSignedNotAllowed)
+	}
+
+	deviceNameSpaces.verify(mobileSecurityObject, report.KeyAuthorizations)
+	return nil
 }
 
 func (ds *DeviceSigned) NameSpaces() (DeviceNameSpaces, error) {
 	var deviceNameSpaces DeviceNameSpaces
-	if err := cbor.Unmarshal(ds.NameSpacesBytes.UntaggedValue, &deviceNameSpaces); err != nil {
+	if err := cbor2.Unmarshal(ds.NameSpacesBytes.UntaggedValue, &deviceNameSpaces); err != nil {
 		return nil, err
 	}
 
@@ -262,22 +420,26 @@ type DeviceNameSpaces map[NameSpace]DeviceSignedItems
 func (dns DeviceNameSpaces) Verify(
 	mobileSecurityObject *MobileSecurityObject,
 ) error {
-	deviceNameSpaceCount := len(dns)
-	if deviceNameSpaceCount == 0 {
-		return nil
-	}
+	keyAuthorizations := make(ElementResults)
+	dns.verify(mobileSecurityObject, keyAuthorizations)
+	return keyAuthorizations.Err()
+}
 
+func (dns DeviceNameSpaces) verify(
+	mobileSecurityObject *MobileSecurityObject,
+	results ElementResults,
+) {
 	keyAuthorizations := mobileSecurityObject.DeviceKeyInfo.KeyAuthorizations
 
 	for nameSpace, deviceSignedItems := range dns {
 		for dataElementIdentifier := range deviceSignedItems {
 			if keyAuthorizations == nil || !keyAuthorizations.Contains(nameSpace, dataElementIdentifier) {
-				return ErrUnauthorizedDeviceNameSpace
+				results.set(nameSpace, dataElementIdentifier, ErrUnauthorizedDeviceNameSpace)
+				continue
 			}
+			results.set(nameSpace, dataElementIdentifier, nil)
 		}
 	}
-
-	return nil
 }
 
 func (dns DeviceNameSpaces) Contains(nameSpace NameSpace, dataElementIdentifier DataElementIdentifier) bool {
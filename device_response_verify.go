@@ -0,0 +1,163 @@
+package mdoc
+
+import (
+	"crypto/x509"
+	"errors"
+	"time"
+)
+
+var (
+	ErrDeviceResponseUnsupportedVersion = errors.New("mdoc: unsupported device response version")
+	ErrDeviceResponseStatus             = errors.New("mdoc: device response status not OK")
+	ErrUnrequestedDocType               = errors.New("mdoc: unrequested doc type")
+	ErrUnrequestedDataElement           = errors.New("mdoc: unrequested data element")
+	ErrMissingDeviceRequest             = errors.New("mdoc: missing device request")
+)
+
+// Claims are data element values disclosed in a verified Document.
+type Claims map[NameSpace]map[DataElementIdentifier]DataElementValue
+
+func (c Claims) set(nameSpace NameSpace, dataElementIdentifier DataElementIdentifier, dataElementValue DataElementValue) {
+	dataElements, ok := c[nameSpace]
+	if !ok {
+		dataElements = make(map[DataElementIdentifier]DataElementValue)
+		c[nameSpace] = dataElements
+	}
+	dataElements[dataElementIdentifier] = dataElementValue
+}
+
+// Get returns the value of a data element, and whether it was disclosed.
+func (c Claims) Get(nameSpace NameSpace, dataElementIdentifier DataElementIdentifier) (DataElementValue, bool) {
+	dataElementValue, ok := c[nameSpace][dataElementIdentifier]
+	return dataElementValue, ok
+}
+
+// VerifiedDocument holds the claims disclosed by a Document that passed verification.
+type VerifiedDocument struct {
+	DocType      DocType
+	ValidityInfo ValidityInfo
+	IssuerSigned Claims
+	DeviceSigned Claims
+	// Errors are the data elements the holder reported as not returned.
+	Errors Errors
+	// Warnings are non-fatal conditions found while verifying the document.
+	Warnings []error
+}
+
+// VerifiedDeviceResponse holds the verified documents of a DeviceResponse.
+type VerifiedDeviceResponse struct {
+	Documents      []VerifiedDocument
+	DocumentErrors []DocumentError
+}
+
+// Verify checks the DeviceResponse version and status, verifies every Document against
+// policy and checks the response only contains doc types and data elements requested by
+// deviceRequest, returning the disclosed claims. A nil policy is DefaultVerifierPolicy; a
+// nil deviceRequest is rejected with ErrMissingDeviceRequest.
+func (dr *DeviceResponse) Verify(
+	policy *VerifierPolicy,
+	deviceRequest *DeviceRequest,
+	rootCertificates []*x509.Certificate,
+	now time.Time,
+	eReaderKey *PrivateKey,
+	sessionTranscript *SessionTranscript,
+) (*VerifiedDeviceResponse, error) {
+	policy = policy.orDefault()
+
+	if dr.Version != DeviceResponseVersion {
+		return nil, ErrDeviceResponseUnsupportedVersion
+	}
+
+	if dr.Status != StatusCodeOK {
+		return nil, ErrDeviceResponseStatus
+	}
+
+	if deviceRequest == nil {
+		return nil, ErrMissingDeviceRequest
+	}
+
+	requestedNameSpaces, err := deviceRequest.requestedNameSpaces()
+	if err != nil {
+		return nil, err
+	}
+
+	verifiedDocuments := make([]VerifiedDocument, len(dr.Documents))
+	for i, document := range dr.Documents {
+		nameSpaces, ok := requestedNameSpaces[document.DocType]
+		if !ok {
+			return nil, ErrUnrequestedDocType
+		}
+
+		verifiedDocument, err := document.verifyClaims(policy, nameSpaces, rootCertificates, now, eReaderKey, sessionTranscript)
+		if err != nil {
+			return nil, err
+		}
+		verifiedDocuments[i] = *verifiedDocument
+	}
+
+	return &VerifiedDeviceResponse{
+		Documents:      verifiedDocuments,
+		DocumentErrors: dr.DocumentErrors,
+	}, nil
+}
+
+func (d *Document) verifyClaims(
+	policy *VerifierPolicy,
+	requestedNameSpaces NameSpaces,
+	rootCertificates []*x509.Certificate,
+	now time.Time,
+	eReaderKey *PrivateKey,
+	sessionTranscript *SessionTranscript,
+) (*VerifiedDocument, error) {
+	report, err := d.VerifyReport(policy, rootCertificates, now, eReaderKey, sessionTranscript)
+	if err != nil {
+		return nil, err
+	}
+	if err = report.Err(); err != nil {
+		return nil, err
+	}
+
+	mobileSecurityObject, err := d.IssuerSigned.IssuerAuth.MobileSecurityObject()
+	if err != nil {
+		return nil, err
+	}
+
+	issuerSignedItems, err := d.IssuerSigned.NameSpaces.IssuerSignedItems()
+	if err != nil {
+		return nil, err
+	}
+
+	issuerSignedClaims := make(Claims)
+	for nameSpace, issuerSignedItems := range issuerSignedItems {
+		for _, issuerSignedItem := range issuerSignedItems {
+			if !requestedNameSpaces.Contains(nameSpace, issuerSignedItem.ElementIdentifier) {
+				return nil, ErrUnrequestedDataElement
+			}
+			issuerSignedClaims.set(nameSpace, issuerSignedItem.ElementIdentifier, issuerSignedItem.ElementValue)
+		}
+	}
+
+	deviceNameSpaces, err := d.DeviceSigned.NameSpaces()
+	if err != nil {
+		return nil, err
+	}
+
+	deviceSignedClaims := make(Claims)
+	for nameSpace, deviceSignedItems := range deviceNameSpaces {
+		for dataElementIdentifier, dataElementValue := range deviceSignedItems {
+			if !requestedNameSpaces.Contains(nameSpace, dataElementIdentifier) {
+				return nil, ErrUnrequestedDataElement
+			}
+			deviceSignedClaims.set(nameSpace, dataElementIdentifier, dataElementValue)
+		}
+	}
+
+	return &VerifiedDocument{
+		DocType:      d.DocType,
+		ValidityInfo: mobileSecurityObject.ValidityInfo,
+		IssuerSigned: issuerSignedClaims,
+		DeviceSigned: deviceSignedClaims,
+		Errors:       d.Errors,
+		Warnings:     report.Warnings,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package mdoc
+
+import (
+	"errors"
+	"time"
+
+	cbor2 "github.com/alex-richards/go-mdoc/internal/cbor"
+)
+
+var (
+	ErrInvalidFullDate = errors.New("mdoc: invalid full-date")
+)
+
+const (
+	TagFullDateString = cbor2.TagFullDateString
+	TagFullDateDays   = cbor2.TagFullDateDays
+	TagTDateString    = cbor2.TagTdate
+
+	FullDateLayout = cbor2.FullDateLayout
+)
+
+// FullDate is a calendar date without a time, encoded as a full-date (RFC 8943).
+// Both the tag 1004 string and tag 100 day count encodings are accepted when decoding,
+// and tdate values are truncated to their date.
+type FullDate struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// NewFullDate returns the date of t in its own location.
+func NewFullDate(t time.Time) FullDate {
+	year, month, day := t.Date()
+	return FullDate{Year: year, Month: month, Day: day}
+}
+
+// Time returns midnight UTC at the start of the date.
+func (fd FullDate) Time() time.Time {
+	return time.Date(fd.Year, fd.Month, fd.Day, 0, 0, 0, 0, time.UTC)
+}
+
+func (fd FullDate) String() string {
+	return fd.Time().Format(FullDateLayout)
+}
+
+func (fd FullDate) MarshalCBOR() ([]byte, error) {
+	return cbor2.MarshalFullDate(fd.Time())
+}
+
+func (fd *FullDate) UnmarshalCBOR(data []byte) error {
+	date, err := cbor2.UnmarshalFullDate(data)
+	if errors.Is(err, cbor2.ErrUnexpectedTag) {
+		date, err = cbor2.UnmarshalTdate(data)
+	}
+	if err != nil {
+		if errors.Is(err, cbor2.ErrUnexpectedTag) || errors.Is(err, cbor2.ErrInvalidDate) {
+			return ErrInvalidFullDate
+		}
+		return err
+	}
+
+	*fd = NewFullDate(date)
+	return nil
+}
@@ -0,0 +1,83 @@
+package mdoc
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_FullDate_MarshalCBOR(t *testing.T) {
+	data, err := cbor.Marshal(FullDate{Year: 1971, Month: time.September, Day: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1004("1971-09-01")
+	want := "d903ec6a313937312d30392d3031"
+	if got := hex.EncodeToString(data); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func Test_FullDate_UnmarshalCBOR(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    FullDate
+		wantErr bool
+	}{
+		{
+			name: "String",
+			data: "d903ec6a313937312d30392d3031",
+			want: FullDate{Year: 1971, Month: time.September, Day: 1},
+		},
+		{
+			name: "Days",
+			data: "d864190269", // 100(617)
+			want: FullDate{Year: 1971, Month: time.September, Day: 10},
+		},
+		{
+			name: "TDate",
+			data: "c074323032302d30332d30345430353a30363a30375a", // 0("2020-03-04T05:06:07Z")
+			want: FullDate{Year: 2020, Month: time.March, Day: 4},
+		},
+		{
+			name:    "Invalid",
+			data:    "d903ec6a313937312d31332d3031",
+			wantErr: true,
+		},
+		{
+			name:    "Untagged",
+			data:    "6a313937312d30392d3031",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got FullDate
+			err = cbor.Unmarshal(data, &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
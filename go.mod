@@ -5,11 +5,14 @@ go 1.23.0
 toolchain go1.23.5
 
 require (
+	filippo.io/edwards25519 v1.1.0
 	github.com/cloudflare/circl v1.6.1
 	github.com/fxamacker/cbor/v2 v2.7.0
 	github.com/jawher/mow.cli v1.2.0
+	github.com/miekg/pkcs11 v1.1.1
 	github.com/veraison/go-cose v1.3.0
 	golang.org/x/crypto v0.36.0
+	rsc.io/qr v0.2.0
 )
 
 require (
@@ -19,10 +22,6 @@ require (
 
 require github.com/google/go-cmp v0.7.0 // test
 
-//replace (
-//    github.com/alex-richards/tiny-cbor => ../tiny-cbor/
-//)
-//
 //replace (
 //    github.com/veraison/go-cose => github.com/alex-richards/go-cose v0.0.0-20240816071327-fa0344c81cf0
 //)
@@ -0,0 +1,353 @@
+package handover
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/alex-richards/go-mdoc/util"
+)
+
+var (
+	ErrInvalidCarrierConfiguration = errors.New("mdoc: handover: invalid carrier configuration")
+	ErrUnsupportedNFCVersion       = errors.New("mdoc: handover: unsupported NFC carrier version")
+)
+
+const (
+	recordTypeBLE       = "application/vnd.bluetooth.le.oob"
+	recordTypeNFC       = "iso.org:18013:nfc"
+	recordTypeWifiAware = "application/vnd.wfa.nan"
+
+	nfcCarrierVersion byte = 0x01
+
+	bleADTypeUUIDs128Incomplete byte = 0x06
+	bleADTypeUUIDs128Complete   byte = 0x07
+	bleADTypeDeviceAddress      byte = 0x1b
+	bleADTypeRole               byte = 0x1c
+
+	bleRolePeripheral          byte = 0x00
+	bleRoleCentral             byte = 0x01
+	bleRolePeripheralPreferred byte = 0x02
+	bleRoleCentralPreferred    byte = 0x03
+
+	bleAddressTypePublic byte = 0x00
+)
+
+// CarrierConfiguration describes a carrier the holder supports for data retrieval, encoded
+// as a carrier configuration record.
+type CarrierConfiguration interface {
+	record(id []byte) Record
+}
+
+// BLECarrier is the "application/vnd.bluetooth.le.oob" carrier configuration.
+// The same service UUID is used for both mdoc peripheral server and central client modes.
+type BLECarrier struct {
+	Options mdoc.BLEOptions
+}
+
+// NFCCarrier is the "iso.org:18013:nfc" carrier configuration.
+type NFCCarrier struct {
+	Options mdoc.NFCOptions
+}
+
+// WifiAwareCarrier is the "application/vnd.wfa.nan" carrier configuration, holding the
+// Wi-Fi Aware attributes unparsed.
+type WifiAwareCarrier struct {
+	Attributes []WifiAwareAttribute
+}
+
+type WifiAwareAttribute struct {
+	ID    byte
+	Value []byte
+}
+
+const (
+	WifiAwareAttributeIDCipherSuiteInfo byte = 0x22
+)
+
+type WifiAwareCipherSuite byte
+
+const (
+	WifiAwareCipherSuiteNCSSK128 WifiAwareCipherSuite = 0x01
+	WifiAwareCipherSuiteNCSSK256 WifiAwareCipherSuite = 0x02
+)
+
+// NewWifiAwareCipherSuiteInfo creates a Cipher Suite Info attribute listing cipherSuites.
+func NewWifiAwareCipherSuiteInfo(cipherSuites ...WifiAwareCipherSuite) WifiAwareAttribute {
+	// capabilities, followed by cipher suite ID and publish ID pairs
+	value := []byte{0x00}
+	for _, cipherSuite := range cipherSuites {
+		value = append(value, byte(cipherSuite), 0x00)
+	}
+
+	return WifiAwareAttribute{
+		ID:    WifiAwareAttributeIDCipherSuiteInfo,
+		Value: value,
+	}
+}
+
+// CipherSuites returns the cipher suites listed in the Cipher Suite Info attribute, or nil
+// if there is none.
+func (c *WifiAwareCarrier) CipherSuites() ([]WifiAwareCipherSuite, error) {
+	for _, attribute := range c.Attributes {
+		if attribute.ID != WifiAwareAttributeIDCipherSuiteInfo {
+			continue
+		}
+
+		if len(attribute.Value) < 1 || (len(attribute.Value)-1)%2 != 0 {
+			return nil, ErrInvalidCarrierConfiguration
+		}
+
+		cipherSuites := make([]WifiAwareCipherSuite, 0, (len(attribute.Value)-1)/2)
+		for i := 1; i < len(attribute.Value); i += 2 {
+			cipherSuites = append(cipherSuites, WifiAwareCipherSuite(attribute.Value[i]))
+		}
+		return cipherSuites, nil
+	}
+
+	return nil, nil
+}
+
+// UnknownCarrier is any other carrier configuration record.
+type UnknownCarrier struct {
+	Record Record
+}
+
+// DeviceRetrievalMethods converts BLE and NFC carriers to the equivalent
+// DeviceRetrievalMethods, other carriers are skipped.
+func DeviceRetrievalMethods(carriers []AlternativeCarrier) []mdoc.DeviceRetrievalMethod {
+	deviceRetrievalMethods := make([]mdoc.DeviceRetrievalMethod, 0, len(carriers))
+	for _, carrier := range carriers {
+		switch configuration := carrier.Configuration.(type) {
+		case *BLECarrier:
+			deviceRetrievalMethods = append(deviceRetrievalMethods, mdoc.DeviceRetrievalMethod{
+				Type:             mdoc.DeviceRetrievalMethodTypeBLE,
+				Version:          mdoc.DeviceRetrievalVersion,
+				RetrievalOptions: configuration.Options,
+			})
+		case *NFCCarrier:
+			deviceRetrievalMethods = append(deviceRetrievalMethods, mdoc.DeviceRetrievalMethod{
+				Type:             mdoc.DeviceRetrievalMethodTypeNFC,
+				Version:          mdoc.DeviceRetrievalVersion,
+				RetrievalOptions: configuration.Options,
+			})
+		}
+	}
+	return deviceRetrievalMethods
+}
+
+func (c *BLECarrier) record(id []byte) Record {
+	var payload []byte
+
+	role := bleRolePeripheral
+	switch {
+	case c.Options.SupportsPeripheralServer && c.Options.SupportsCentralClient:
+		role = bleRolePeripheralPreferred
+	case c.Options.SupportsCentralClient:
+		role = bleRoleCentral
+	}
+	payload = append(payload, 2, bleADTypeRole, role)
+
+	uuid := c.Options.PeripheralServerUUID
+	if uuid == nil {
+		uuid = c.Options.CentralClientUUID
+	}
+	if uuid != nil {
+		payload = append(payload, byte(1+len(uuid)), bleADTypeUUIDs128Complete)
+		for i := len(uuid) - 1; i >= 0; i-- {
+			payload = append(payload, uuid[i])
+		}
+	}
+
+	if address := c.Options.PeripheralServerDeviceAddress; address != nil {
+		payload = append(payload, byte(2+len(address)), bleADTypeDeviceAddress)
+		for i := len(address) - 1; i >= 0; i-- {
+			payload = append(payload, address[i])
+		}
+		payload = append(payload, bleAddressTypePublic)
+	}
+
+	return Record{
+		TNF:     TNFMediaType,
+		Type:    []byte(recordTypeBLE),
+		ID:      id,
+		Payload: payload,
+	}
+}
+
+func parseBLECarrier(payload []byte) (*BLECarrier, error) {
+	var options mdoc.BLEOptions
+	var uuid *util.UUID
+
+	for len(payload) > 0 {
+		length := int(payload[0])
+		if length == 0 || len(payload) < 1+length {
+			return nil, ErrInvalidCarrierConfiguration
+		}
+		adType, data := payload[1], payload[2:1+length]
+		payload = payload[1+length:]
+
+		switch adType {
+		case bleADTypeRole:
+			if len(data) != 1 {
+				return nil, ErrInvalidCarrierConfiguration
+			}
+			switch data[0] {
+			case bleRolePeripheral:
+				options.SupportsPeripheralServer = true
+			case bleRoleCentral:
+				options.SupportsCentralClient = true
+			case bleRolePeripheralPreferred, bleRoleCentralPreferred:
+				options.SupportsPeripheralServer = true
+				options.SupportsCentralClient = true
+			default:
+				return nil, ErrInvalidCarrierConfiguration
+			}
+
+		case bleADTypeUUIDs128Complete, bleADTypeUUIDs128Incomplete:
+			if len(data) == 0 || len(data)%len(util.UUID{}) != 0 {
+				return nil, ErrInvalidCarrierConfiguration
+			}
+			uuid = new(util.UUID)
+			for i := range uuid {
+				uuid[i] = data[len(uuid)-1-i]
+			}
+
+		case bleADTypeDeviceAddress:
+			address := new(mdoc.BLEAddress)
+			if len(data) != len(address)+1 {
+				return nil, ErrInvalidCarrierConfiguration
+			}
+			for i := range address {
+				address[i] = data[len(address)-1-i]
+			}
+			options.PeripheralServerDeviceAddress = address
+		}
+	}
+
+	if options.SupportsPeripheralServer {
+		options.PeripheralServerUUID = uuid
+	}
+	if options.SupportsCentralClient {
+		options.CentralClientUUID = uuid
+	}
+
+	return &BLECarrier{Options: options}, nil
+}
+
+func (c *NFCCarrier) record(id []byte) Record {
+	payload := []byte{nfcCarrierVersion}
+	payload = appendNFCDataLength(payload, c.Options.MaxLengthCommandData)
+	payload = appendNFCDataLength(payload, c.Options.MaxLengthResponseData)
+
+	return Record{
+		TNF:     TNFExternal,
+		Type:    []byte(recordTypeNFC),
+		ID:      id,
+		Payload: payload,
+	}
+}
+
+func parseNFCCarrier(payload []byte) (*NFCCarrier, error) {
+	if len(payload) < 1 {
+		return nil, ErrInvalidCarrierConfiguration
+	}
+	if payload[0] != nfcCarrierVersion {
+		return nil, ErrUnsupportedNFCVersion
+	}
+	payload = payload[1:]
+
+	var options mdoc.NFCOptions
+	var err error
+	if options.MaxLengthCommandData, payload, err = readNFCDataLength(payload); err != nil {
+		return nil, err
+	}
+	if options.MaxLengthResponseData, payload, err = readNFCDataLength(payload); err != nil {
+		return nil, err
+	}
+	if len(payload) > 0 {
+		return nil, ErrInvalidCarrierConfiguration
+	}
+
+	return &NFCCarrier{Options: options}, nil
+}
+
+func appendNFCDataLength(to []byte, length uint) []byte {
+	var value []byte
+	for ; length > 0; length >>= 8 {
+		value = append([]byte{byte(length)}, value...)
+	}
+	to = append(to, byte(len(value)))
+	return append(to, value...)
+}
+
+func readNFCDataLength(from []byte) (uint, []byte, error) {
+	if len(from) < 1 {
+		return 0, nil, ErrInvalidCarrierConfiguration
+	}
+	valueLength := int(from[0])
+	if valueLength > 4 || len(from) < 1+valueLength {
+		return 0, nil, ErrInvalidCarrierConfiguration
+	}
+
+	var length uint
+	for _, b := range from[1 : 1+valueLength] {
+		length = length<<8 | uint(b)
+	}
+	return length, from[1+valueLength:], nil
+}
+
+func (c *WifiAwareCarrier) record(id []byte) Record {
+	var payload []byte
+	for _, attribute := range c.Attributes {
+		payload = append(payload, attribute.ID)
+		payload = binary.LittleEndian.AppendUint16(payload, uint16(len(attribute.Value)))
+		payload = append(payload, attribute.Value...)
+	}
+
+	return Record{
+		TNF:     TNFMediaType,
+		Type:    []byte(recordTypeWifiAware),
+		ID:      id,
+		Payload: payload,
+	}
+}
+
+func parseWifiAwareCarrier(payload []byte) (*WifiAwareCarrier, error) {
+	var attributes []WifiAwareAttribute
+	for len(payload) > 0 {
+		if len(payload) < 3 {
+			return nil, ErrInvalidCarrierConfiguration
+		}
+		length := int(binary.LittleEndian.Uint16(payload[1:]))
+		if len(payload) < 3+length {
+			return nil, ErrInvalidCarrierConfiguration
+		}
+		attributes = append(attributes, WifiAwareAttribute{
+			ID:    payload[0],
+			Value: payload[3 : 3+length],
+		})
+		payload = payload[3+length:]
+	}
+
+	return &WifiAwareCarrier{Attributes: attributes}, nil
+}
+
+func (c *UnknownCarrier) record(id []byte) Record {
+	record := c.Record
+	record.ID = id
+	return record
+}
+
+func parseCarrier(record *Record) (CarrierConfiguration, error) {
+	switch {
+	case record.TNF == TNFMediaType && string(record.Type) == recordTypeBLE:
+		return parseBLECarrier(record.Payload)
+	case record.TNF == TNFExternal && string(record.Type) == recordTypeNFC:
+		return parseNFCCarrier(record.Payload)
+	case record.TNF == TNFMediaType && string(record.Type) == recordTypeWifiAware:
+		return parseWifiAwareCarrier(record.Payload)
+	default:
+		return &UnknownCarrier{Record: *record}, nil
+	}
+}
@@ -0,0 +1,263 @@
+package handover
+
+import (
+	"encoding/binary"
+	"errors"
+	"strconv"
+
+	"github.com/alex-richards/go-mdoc"
+)
+
+var (
+	ErrMissingHandoverRecord       = errors.New("mdoc: handover: missing handover record")
+	ErrMissingDeviceEngagement     = errors.New("mdoc: handover: missing device engagement record")
+	ErrMissingCarrierConfiguration = errors.New("mdoc: handover: missing carrier configuration record")
+	ErrUnsupportedHandoverVersion  = errors.New("mdoc: handover: unsupported handover version")
+	ErrInvalidAlternativeCarrier   = errors.New("mdoc: handover: invalid alternative carrier record")
+	ErrMissingCollisionResolution  = errors.New("mdoc: handover: missing collision resolution record")
+	ErrInvalidCollisionResolution  = errors.New("mdoc: handover: invalid collision resolution record")
+	ErrTooManyAlternativeCarriers  = errors.New("mdoc: handover: too many alternative carriers")
+)
+
+const (
+	recordTypeHandoverSelect      = "Hs"
+	recordTypeHandoverRequest     = "Hr"
+	recordTypeAlternativeCarrier  = "ac"
+	recordTypeCollisionResolution = "cr"
+	recordTypeDeviceEngagement    = "iso.org:18013:deviceengagement"
+
+	handoverVersion byte = 0x15
+)
+
+var deviceEngagementRecordID = []byte("mdoc")
+
+type CarrierPowerState byte
+
+const (
+	CarrierPowerStateInactive   CarrierPowerState = 0x00
+	CarrierPowerStateActive     CarrierPowerState = 0x01
+	CarrierPowerStateActivating CarrierPowerState = 0x02
+	CarrierPowerStateUnknown    CarrierPowerState = 0x03
+)
+
+type AlternativeCarrier struct {
+	PowerState    CarrierPowerState
+	Configuration CarrierConfiguration
+}
+
+// HandoverSelect is the Handover Select message sent by the holder, in both static and
+// negotiated handover, carrying the DeviceEngagement.
+type HandoverSelect struct {
+	DeviceEngagementBytes []byte
+	AlternativeCarriers   []AlternativeCarrier
+}
+
+// HandoverRequest is the Handover Request message sent by the reader in negotiated handover.
+type HandoverRequest struct {
+	CollisionResolution uint16
+	AlternativeCarriers []AlternativeCarrier
+}
+
+// MarshalNDEF encodes the Handover Select message. The encoded bytes are used as
+// HandoverSelect in the NFCHandover.
+func (hs *HandoverSelect) MarshalNDEF() ([]byte, error) {
+	if hs.DeviceEngagementBytes == nil {
+		return nil, ErrMissingDeviceEngagement
+	}
+
+	deviceEngagementRecord := Record{
+		TNF:     TNFExternal,
+		Type:    []byte(recordTypeDeviceEngagement),
+		ID:      deviceEngagementRecordID,
+		Payload: hs.DeviceEngagementBytes,
+	}
+
+	return marshalHandover(recordTypeHandoverSelect, nil, hs.AlternativeCarriers, deviceEngagementRecord)
+}
+
+// ParseHandoverSelect decodes a Handover Select message.
+func ParseHandoverSelect(message []byte) (*HandoverSelect, error) {
+	records, embeddedRecords, err := parseHandover(message, recordTypeHandoverSelect)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceEngagementRecord, ok := findRecord(records, TNFExternal, recordTypeDeviceEngagement)
+	if !ok {
+		return nil, ErrMissingDeviceEngagement
+	}
+
+	alternativeCarriers, err := parseAlternativeCarriers(records, embeddedRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HandoverSelect{
+		DeviceEngagementBytes: deviceEngagementRecord.Payload,
+		AlternativeCarriers:   alternativeCarriers,
+	}, nil
+}
+
+// MarshalNDEF encodes the Handover Request message. The encoded bytes are used as
+// HandoverRequest in the NFCHandover.
+func (hr *HandoverRequest) MarshalNDEF() ([]byte, error) {
+	collisionResolutionRecord := Record{
+		TNF:     TNFWellKnown,
+		Type:    []byte(recordTypeCollisionResolution),
+		Payload: binary.BigEndian.AppendUint16(nil, hr.CollisionResolution),
+	}
+
+	return marshalHandover(recordTypeHandoverRequest, &collisionResolutionRecord, hr.AlternativeCarriers)
+}
+
+// ParseHandoverRequest decodes a Handover Request message.
+func ParseHandoverRequest(message []byte) (*HandoverRequest, error) {
+	records, embeddedRecords, err := parseHandover(message, recordTypeHandoverRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	collisionResolutionRecord, ok := findRecord(embeddedRecords, TNFWellKnown, recordTypeCollisionResolution)
+	if !ok {
+		return nil, ErrMissingCollisionResolution
+	}
+	if len(collisionResolutionRecord.Payload) != 2 {
+		return nil, ErrInvalidCollisionResolution
+	}
+
+	alternativeCarriers, err := parseAlternativeCarriers(records, embeddedRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HandoverRequest{
+		CollisionResolution: binary.BigEndian.Uint16(collisionResolutionRecord.Payload),
+		AlternativeCarriers: alternativeCarriers,
+	}, nil
+}
+
+// NewNFCHandover creates the SessionTranscript Handover from the encoded messages exactly as
+// exchanged, handoverRequestMessage is nil for static handover.
+func NewNFCHandover(handoverSelectMessage []byte, handoverRequestMessage []byte) mdoc.NFCHandover {
+	return mdoc.NFCHandover{
+		HandoverSelect:  handoverSelectMessage,
+		HandoverRequest: handoverRequestMessage,
+	}
+}
+
+func marshalHandover(
+	recordType string,
+	collisionResolutionRecord *Record,
+	alternativeCarriers []AlternativeCarrier,
+	auxiliaryRecords ...Record,
+) ([]byte, error) {
+	if len(alternativeCarriers) > 0xff {
+		return nil, ErrTooManyAlternativeCarriers
+	}
+
+	var embeddedRecords []Record
+	if collisionResolutionRecord != nil {
+		embeddedRecords = append(embeddedRecords, *collisionResolutionRecord)
+	}
+
+	carrierRecords := make([]Record, 0, len(alternativeCarriers))
+	for i, alternativeCarrier := range alternativeCarriers {
+		id := []byte(strconv.Itoa(i))
+		carrierRecords = append(carrierRecords, alternativeCarrier.Configuration.record(id))
+
+		payload := []byte{byte(alternativeCarrier.PowerState) & 0x03, byte(len(id))}
+		payload = append(payload, id...)
+		payload = append(payload, byte(len(auxiliaryRecords)))
+		for _, auxiliaryRecord := range auxiliaryRecords {
+			payload = append(payload, byte(len(auxiliaryRecord.ID)))
+			payload = append(payload, auxiliaryRecord.ID...)
+		}
+
+		embeddedRecords = append(embeddedRecords, Record{
+			TNF:     TNFWellKnown,
+			Type:    []byte(recordTypeAlternativeCarrier),
+			Payload: payload,
+		})
+	}
+
+	handoverPayload := []byte{handoverVersion}
+	if len(embeddedRecords) > 0 {
+		embeddedMessage, err := MarshalMessage(embeddedRecords)
+		if err != nil {
+			return nil, err
+		}
+		handoverPayload = append(handoverPayload, embeddedMessage...)
+	}
+
+	records := []Record{{
+		TNF:     TNFWellKnown,
+		Type:    []byte(recordType),
+		Payload: handoverPayload,
+	}}
+	records = append(records, carrierRecords...)
+	records = append(records, auxiliaryRecords...)
+
+	return MarshalMessage(records)
+}
+
+func parseHandover(message []byte, recordType string) ([]Record, []Record, error) {
+	records, err := ParseMessage(message)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	handoverRecord := &records[0]
+	if handoverRecord.TNF != TNFWellKnown || string(handoverRecord.Type) != recordType {
+		return nil, nil, ErrMissingHandoverRecord
+	}
+	if len(handoverRecord.Payload) < 1 {
+		return nil, nil, ErrUnsupportedHandoverVersion
+	}
+	// only the major version is significant
+	if handoverRecord.Payload[0]>>4 != handoverVersion>>4 {
+		return nil, nil, ErrUnsupportedHandoverVersion
+	}
+
+	var embeddedRecords []Record
+	if len(handoverRecord.Payload) > 1 {
+		embeddedRecords, err = ParseMessage(handoverRecord.Payload[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return records[1:], embeddedRecords, nil
+}
+
+func parseAlternativeCarriers(records []Record, embeddedRecords []Record) ([]AlternativeCarrier, error) {
+	var alternativeCarriers []AlternativeCarrier
+	for _, embeddedRecord := range embeddedRecords {
+		if embeddedRecord.TNF != TNFWellKnown || string(embeddedRecord.Type) != recordTypeAlternativeCarrier {
+			continue
+		}
+
+		payload := embeddedRecord.Payload
+		if len(payload) < 2 || len(payload) < 2+int(payload[1]) {
+			return nil, ErrInvalidAlternativeCarrier
+		}
+		powerState := CarrierPowerState(payload[0] & 0x03)
+		carrierDataReference := payload[2 : 2+int(payload[1])]
+
+		carrierRecord, ok := findRecordByID(records, carrierDataReference)
+		if !ok {
+			return nil, ErrMissingCarrierConfiguration
+		}
+
+		configuration, err := parseCarrier(carrierRecord)
+		if err != nil {
+			return nil, err
+		}
+
+		alternativeCarriers = append(alternativeCarriers, AlternativeCarrier{
+			PowerState:    powerState,
+			Configuration: configuration,
+		})
+	}
+
+	return alternativeCarriers, nil
+}
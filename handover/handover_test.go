@@ -0,0 +1,239 @@
+package handover
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/alex-richards/go-mdoc/util"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_HandoverSelect_MarshalNDEF(t *testing.T) {
+	handoverSelect := HandoverSelect{
+		DeviceEngagementBytes: []byte{0xa0},
+		AlternativeCarriers: []AlternativeCarrier{{
+			PowerState: CarrierPowerStateActive,
+			Configuration: &NFCCarrier{Options: mdoc.NFCOptions{
+				MaxLengthCommandData:  255,
+				MaxLengthResponseData: 256,
+			}},
+		}},
+	}
+
+	want := "91020f" + hex.EncodeToString([]byte("Hs")) + "15" +
+		"d10209" + hex.EncodeToString([]byte("ac")) + "010130" + "0104" + hex.EncodeToString([]byte("mdoc")) +
+		"1c110601" + hex.EncodeToString([]byte("iso.org:18013:nfc")) + "30" + "0101ff020100" +
+		"5c1e0104" + hex.EncodeToString([]byte("iso.org:18013:deviceengagement")) + hex.EncodeToString([]byte("mdoc")) + "a0"
+
+	got, err := handoverSelect.MarshalNDEF()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hex.EncodeToString(got) != want {
+		t.Fatalf("expected %s, got %x", want, got)
+	}
+}
+
+func Test_HandoverSelect_RoundTrip(t *testing.T) {
+	uuid := util.UUID{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f}
+	address := mdoc.BLEAddress{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}
+
+	tests := []struct {
+		name           string
+		handoverSelect HandoverSelect
+	}{
+		{
+			name: "No carriers",
+			handoverSelect: HandoverSelect{
+				DeviceEngagementBytes: []byte{1, 2, 3, 4},
+			},
+		},
+		{
+			name: "BLE peripheral server",
+			handoverSelect: HandoverSelect{
+				DeviceEngagementBytes: []byte{1, 2, 3, 4},
+				AlternativeCarriers: []AlternativeCarrier{{
+					PowerState: CarrierPowerStateActive,
+					Configuration: &BLECarrier{Options: mdoc.BLEOptions{
+						SupportsPeripheralServer:      true,
+						PeripheralServerUUID:          &uuid,
+						PeripheralServerDeviceAddress: &address,
+					}},
+				}},
+			},
+		},
+		{
+			name: "BLE both modes",
+			handoverSelect: HandoverSelect{
+				DeviceEngagementBytes: []byte{1, 2, 3, 4},
+				AlternativeCarriers: []AlternativeCarrier{{
+					PowerState: CarrierPowerStateActivating,
+					Configuration: &BLECarrier{Options: mdoc.BLEOptions{
+						SupportsPeripheralServer: true,
+						SupportsCentralClient:    true,
+						PeripheralServerUUID:     &uuid,
+						CentralClientUUID:        &uuid,
+					}},
+				}},
+			},
+		},
+		{
+			name: "All carriers",
+			handoverSelect: HandoverSelect{
+				DeviceEngagementBytes: bytes.Repeat([]byte{0xaa}, 300),
+				AlternativeCarriers: []AlternativeCarrier{
+					{
+						PowerState: CarrierPowerStateActive,
+						Configuration: &BLECarrier{Options: mdoc.BLEOptions{
+							SupportsCentralClient: true,
+							CentralClientUUID:     &uuid,
+						}},
+					},
+					{
+						PowerState: CarrierPowerStateActive,
+						Configuration: &NFCCarrier{Options: mdoc.NFCOptions{
+							MaxLengthCommandData:  0xffff,
+							MaxLengthResponseData: 0x10000,
+						}},
+					},
+					{
+						PowerState: CarrierPowerStateInactive,
+						Configuration: &WifiAwareCarrier{Attributes: []WifiAwareAttribute{
+							{ID: 0x22, Value: []byte{0x00, 0x01}},
+							{ID: 0x03, Value: []byte("passphrase")},
+						}},
+					},
+					{
+						PowerState: CarrierPowerStateUnknown,
+						Configuration: &UnknownCarrier{Record: Record{
+							TNF:     TNFMediaType,
+							Type:    []byte("application/x-test"),
+							ID:      []byte("3"),
+							Payload: []byte{1, 2, 3},
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := tt.handoverSelect.MarshalNDEF()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			handoverSelect, err := ParseHandoverSelect(message)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tt.handoverSelect, *handoverSelect); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_HandoverRequest_RoundTrip(t *testing.T) {
+	handoverRequest := HandoverRequest{
+		CollisionResolution: 0x1234,
+		AlternativeCarriers: []AlternativeCarrier{{
+			PowerState: CarrierPowerStateActive,
+			Configuration: &NFCCarrier{Options: mdoc.NFCOptions{
+				MaxLengthCommandData:  255,
+				MaxLengthResponseData: 256,
+			}},
+		}},
+	}
+
+	message, err := handoverRequest.MarshalNDEF()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ParseHandoverRequest(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(handoverRequest, *got); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if _, err = ParseHandoverSelect(message); !errors.Is(err, ErrMissingHandoverRecord) {
+		t.Fatalf("expected %v, got %v", ErrMissingHandoverRecord, err)
+	}
+}
+
+func Test_NFCHandover_SessionTranscript(t *testing.T) {
+	handoverSelectMessage, err := (&HandoverSelect{DeviceEngagementBytes: []byte{0xa0}}).MarshalNDEF()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handoverRequestMessage, err := (&HandoverRequest{CollisionResolution: 1}).MarshalNDEF()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nfcHandover := NewNFCHandover(handoverSelectMessage, handoverRequestMessage)
+	if !bytes.Equal(nfcHandover.HandoverSelect, handoverSelectMessage) ||
+		!bytes.Equal(nfcHandover.HandoverRequest, handoverRequestMessage) {
+		t.Fatal("handover messages not preserved")
+	}
+}
+
+func Test_ParseMessage_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    error
+	}{
+		{name: "Empty", message: "", want: ErrEmptyNDEFMessage},
+		{name: "Truncated", message: "d1010a55", want: ErrInvalidNDEF},
+		{name: "Missing MB", message: "51010155", want: ErrInvalidNDEF},
+		{name: "Missing ME", message: "91010055", want: ErrInvalidNDEF},
+		{name: "Chunked", message: "b1010055", want: ErrChunkedRecord},
+		{name: "Trailing bytes", message: "d101005500", want: ErrTrailingNDEFBytes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := hex.DecodeString(tt.message)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = ParseMessage(message); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func Test_WifiAwareCarrier_CipherSuites(t *testing.T) {
+	carrier := WifiAwareCarrier{Attributes: []WifiAwareAttribute{
+		NewWifiAwareCipherSuiteInfo(WifiAwareCipherSuiteNCSSK128, WifiAwareCipherSuiteNCSSK256),
+	}}
+
+	cipherSuites, err := carrier.CipherSuites()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []WifiAwareCipherSuite{WifiAwareCipherSuiteNCSSK128, WifiAwareCipherSuiteNCSSK256}
+	if diff := cmp.Diff(want, cipherSuites); diff != "" {
+		t.Fatal(diff)
+	}
+
+	carrier.Attributes[0].Value = carrier.Attributes[0].Value[:2]
+	if _, err = carrier.CipherSuites(); !errors.Is(err, ErrInvalidCarrierConfiguration) {
+		t.Fatalf("expected %v, got %v", ErrInvalidCarrierConfiguration, err)
+	}
+}
@@ -0,0 +1,184 @@
+package handover
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var (
+	ErrInvalidNDEF       = errors.New("mdoc: handover: invalid NDEF message")
+	ErrChunkedRecord     = errors.New("mdoc: handover: chunked NDEF records are not supported")
+	ErrRecordTooLarge    = errors.New("mdoc: handover: NDEF record too large")
+	ErrEmptyNDEFMessage  = errors.New("mdoc: handover: empty NDEF message")
+	ErrTypeTooLong       = errors.New("mdoc: handover: NDEF record type too long")
+	ErrIDTooLong         = errors.New("mdoc: handover: NDEF record ID too long")
+	ErrTrailingNDEFBytes = errors.New("mdoc: handover: trailing bytes after NDEF message")
+)
+
+type TNF byte
+
+const (
+	TNFEmpty       TNF = 0x00
+	TNFWellKnown   TNF = 0x01
+	TNFMediaType   TNF = 0x02
+	TNFAbsoluteURI TNF = 0x03
+	TNFExternal    TNF = 0x04
+	TNFUnknown     TNF = 0x05
+	TNFUnchanged   TNF = 0x06
+)
+
+const (
+	ndefFlagMB  byte = 0x80
+	ndefFlagME  byte = 0x40
+	ndefFlagCF  byte = 0x20
+	ndefFlagSR  byte = 0x10
+	ndefFlagIL  byte = 0x08
+	ndefMaskTNF byte = 0x07
+)
+
+// Record is a single, unchunked, NDEF record.
+type Record struct {
+	TNF     TNF
+	Type    []byte
+	ID      []byte
+	Payload []byte
+}
+
+// MarshalMessage encodes records as an NDEF message, setting the message begin and end
+// flags on the first and last records.
+func MarshalMessage(records []Record) ([]byte, error) {
+	if len(records) == 0 {
+		return nil, ErrEmptyNDEFMessage
+	}
+
+	var message []byte
+	for i, record := range records {
+		if len(record.Type) > 0xff {
+			return nil, ErrTypeTooLong
+		}
+		if len(record.ID) > 0xff {
+			return nil, ErrIDTooLong
+		}
+		if uint64(len(record.Payload)) > 0xffffffff {
+			return nil, ErrRecordTooLarge
+		}
+
+		header := byte(record.TNF) & ndefMaskTNF
+		if i == 0 {
+			header |= ndefFlagMB
+		}
+		if i == len(records)-1 {
+			header |= ndefFlagME
+		}
+		shortRecord := len(record.Payload) <= 0xff
+		if shortRecord {
+			header |= ndefFlagSR
+		}
+		if len(record.ID) > 0 {
+			header |= ndefFlagIL
+		}
+
+		message = append(message, header, byte(len(record.Type)))
+		if shortRecord {
+			message = append(message, byte(len(record.Payload)))
+		} else {
+			message = binary.BigEndian.AppendUint32(message, uint32(len(record.Payload)))
+		}
+		if len(record.ID) > 0 {
+			message = append(message, byte(len(record.ID)))
+		}
+		message = append(message, record.Type...)
+		message = append(message, record.ID...)
+		message = append(message, record.Payload...)
+	}
+
+	return message, nil
+}
+
+// ParseMessage decodes an NDEF message.
+func ParseMessage(message []byte) ([]Record, error) {
+	if len(message) == 0 {
+		return nil, ErrEmptyNDEFMessage
+	}
+
+	var records []Record
+	for len(message) > 0 {
+		if len(message) < 3 {
+			return nil, ErrInvalidNDEF
+		}
+
+		header := message[0]
+		if (header&ndefFlagMB != 0) != (len(records) == 0) {
+			return nil, ErrInvalidNDEF
+		}
+		if header&ndefFlagCF != 0 {
+			return nil, ErrChunkedRecord
+		}
+
+		typeLength := int(message[1])
+		message = message[2:]
+
+		var payloadLength int
+		if header&ndefFlagSR != 0 {
+			payloadLength = int(message[0])
+			message = message[1:]
+		} else {
+			if len(message) < 4 {
+				return nil, ErrInvalidNDEF
+			}
+			payloadLength = int(binary.BigEndian.Uint32(message))
+			message = message[4:]
+		}
+
+		var idLength int
+		if header&ndefFlagIL != 0 {
+			if len(message) < 1 {
+				return nil, ErrInvalidNDEF
+			}
+			idLength = int(message[0])
+			message = message[1:]
+		}
+
+		if len(message) < typeLength+idLength || len(message)-typeLength-idLength < payloadLength {
+			return nil, ErrInvalidNDEF
+		}
+
+		record := Record{TNF: TNF(header & ndefMaskTNF)}
+		record.Type, message = message[:typeLength], message[typeLength:]
+		if idLength > 0 {
+			record.ID, message = message[:idLength], message[idLength:]
+		}
+		record.Payload, message = message[:payloadLength], message[payloadLength:]
+
+		records = append(records, record)
+
+		if header&ndefFlagME != 0 {
+			if len(message) > 0 {
+				return nil, ErrTrailingNDEFBytes
+			}
+			return records, nil
+		}
+	}
+
+	return nil, ErrInvalidNDEF
+}
+
+// findRecord returns the first record matching tnf and recordType.
+func findRecord(records []Record, tnf TNF, recordType string) (*Record, bool) {
+	for i := range records {
+		if records[i].TNF == tnf && string(records[i].Type) == recordType {
+			return &records[i], true
+		}
+	}
+	return nil, false
+}
+
+// findRecordByID returns the first record with id.
+func findRecordByID(records []Record, id []byte) (*Record, bool) {
+	for i := range records {
+		if len(records[i].ID) > 0 && string(records[i].ID) == string(id) {
+			return &records[i], true
+		}
+	}
+	return nil, false
+}
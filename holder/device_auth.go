@@ -5,14 +5,17 @@ import (
 
 	"github.com/alex-richards/go-mdoc"
 	"github.com/alex-richards/go-mdoc/internal/cbor"
+	cose2 "github.com/alex-richards/go-mdoc/internal/cose"
 	"github.com/veraison/go-cose"
 )
 
-// NewDeviceAuth creates a new DeviceAuth, signed using the provided SDeviceKey.
-// TODO MAC auth
+// NewDeviceAuth creates a new DeviceAuth, signed or MACed using the provided SDeviceKey.
+// A MAC is used when the SDeviceKey can only be used for key agreement, with the
+// EMacKey derived from the EReaderKey in sessionTranscript.
 func NewDeviceAuth(
 	rand io.Reader,
 	privateSDeviceKey *mdoc.PrivateKey,
+	sessionTranscript *mdoc.SessionTranscript,
 	deviceAuthenticationBytes *cbor.TaggedEncodedCBOR,
 ) (*mdoc.DeviceAuth, error) {
 	switch {
@@ -20,7 +23,7 @@ func NewDeviceAuth(
 		return newSignedDeviceAuth(rand, privateSDeviceKey, deviceAuthenticationBytes)
 
 	case privateSDeviceKey.Signer == nil && privateSDeviceKey.Agreer != nil:
-		return newTaggedDeviceAuth()
+		return newMACedDeviceAuth(privateSDeviceKey, sessionTranscript, deviceAuthenticationBytes)
 
 	default:
 		panic("invalid PrivateSDeviceKey")
@@ -32,10 +35,12 @@ func newSignedDeviceAuth(
 	signer *mdoc.PrivateKey,
 	deviceAuthenticationBytes *cbor.TaggedEncodedCBOR,
 ) (*mdoc.DeviceAuth, error) {
+	coseSigner := mdoc.CoseSigner{Signer: signer.Signer}
+
 	deviceAuth := &mdoc.DeviceAuth{DeviceSignature: &mdoc.DeviceSignature{
 		Headers: cose.Headers{
 			Protected: map[any]any{
-				cose.HeaderLabelAlgorithm: signer.PublicKey.Algorithm,
+				cose.HeaderLabelAlgorithm: coseSigner.Algorithm(),
 			},
 		},
 	}}
@@ -43,7 +48,7 @@ func newSignedDeviceAuth(
 	sign1 := (cose.Sign1Message)(*deviceAuth.DeviceSignature)
 	sign1.Payload = deviceAuthenticationBytes.TaggedValue
 
-	err := sign1.Sign(rand, []byte{}, mdoc.CoseSigner{Signer: signer.Signer})
+	err := sign1.Sign(rand, []byte{}, coseSigner)
 	if err != nil {
 		return nil, err
 	}
@@ -53,6 +58,36 @@ func newSignedDeviceAuth(
 	return deviceAuth, nil
 }
 
-func newTaggedDeviceAuth() (*mdoc.DeviceAuth, error) {
-	return nil, mdoc.ErrMACAuthNotSupported
+func newMACedDeviceAuth(
+	agreer *mdoc.PrivateKey,
+	sessionTranscript *mdoc.SessionTranscript,
+	deviceAuthenticationBytes *cbor.TaggedEncodedCBOR,
+) (*mdoc.DeviceAuth, error) {
+	eReaderKey, err := sessionTranscript.EReaderKey()
+	if err != nil {
+		return nil, err
+	}
+
+	sessionTranscriptBytes, err := mdoc.NewSessionTranscriptBytes(sessionTranscript)
+	if err != nil {
+		return nil, err
+	}
+
+	eMacKey, err := mdoc.EMacKey(agreer.Agreer, eReaderKey, sessionTranscriptBytes.TaggedValue)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceMAC := &mdoc.DeviceMAC{
+		Payload: deviceAuthenticationBytes.TaggedValue,
+	}
+
+	err = (*cose2.Mac0Message)(deviceMAC).CreateTag([]byte{}, eMacKey)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceMAC.Payload = nil
+
+	return &mdoc.DeviceAuth{DeviceMAC: deviceMAC}, nil
 }
@@ -129,7 +129,7 @@ func NewDeviceSigned(
 		return nil, err
 	}
 
-	deviceAuth, err := NewDeviceAuth(rand, privateSDeviceKey, deviceAuthenticationBytes)
+	deviceAuth, err := NewDeviceAuth(rand, privateSDeviceKey, sessionTranscript, deviceAuthenticationBytes)
 	if err != nil {
 		return nil, err
 	}
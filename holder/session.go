@@ -0,0 +1,340 @@
+package holder
+
+import (
+	"crypto/x509"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/alex-richards/go-mdoc/cipher_suite"
+	mdoccbor "github.com/alex-richards/go-mdoc/internal/cbor"
+	cose2 "github.com/alex-richards/go-mdoc/internal/cose"
+	"github.com/alex-richards/go-mdoc/session"
+)
+
+var (
+	ErrUnexpectedSessionState = errors.New("mdoc: holder: unexpected session state")
+	ErrMissingSessionData     = errors.New("mdoc: holder: missing session data")
+)
+
+type SessionState int
+
+const (
+	SessionStateEngaged SessionState = iota
+	SessionStateEstablished
+	SessionStateTerminated
+)
+
+// ConsentFunc is called for each requested document the holder can provide, and returns
+// the subset of nameSpaces approved for release.
+// readerCertificate is the verified reader authentication certificate, or nil if the
+// request was not authenticated.
+type ConsentFunc func(
+	docType mdoc.DocType,
+	nameSpaces mdoc.NameSpaces,
+	readerCertificate *x509.Certificate,
+) (mdoc.NameSpaces, error)
+
+// Session drives the holder side of a device retrieval transaction, from DeviceEngagement
+// through to session termination.
+type Session struct {
+	rand             io.Reader
+	issuerSigneds    map[mdoc.DocType]mdoc.IssuerSigned
+	sDeviceKey       *mdoc.PrivateKey
+	rootCertificates []*x509.Certificate
+	policy           *mdoc.VerifierPolicy
+	consent          ConsentFunc
+	options          session.SessionEncryptionOptions
+
+	state                 SessionState
+	eDeviceKey            *mdoc.PrivateKey
+	deviceEngagement      *mdoc.DeviceEngagement
+	deviceEngagementBytes *mdoccbor.TaggedEncodedCBOR
+	handover              mdoc.Handover
+	sessionTranscript     *mdoc.SessionTranscript
+	sessionEncryption     *session.SessionEncryption
+}
+
+// NewSession generates a new EDeviceKey on curve and the DeviceEngagement advertising it.
+// rootCertificates are used to verify reader authentication, and consent is asked to
+// approve every request before a response is sent.
+// policy may be nil, in which case mdoc.DefaultVerifierPolicy is used, and requests
+// without reader authentication are rejected.
+// options may be nil, in which case the session is encrypted with AES-256-GCM; any other
+// cipher suite is advertised in the DeviceEngagement. With RetainCounterOnFailure set, a
+// request which fails to decrypt does not terminate the session, so it may be resent.
+// DeviceRequests are decoded within DecodeLimits.
+func NewSession(
+	rand io.Reader,
+	curve mdoc.Curve,
+	deviceRetrievalMethods []mdoc.DeviceRetrievalMethod,
+	handover mdoc.Handover,
+	issuerSigneds map[mdoc.DocType]mdoc.IssuerSigned,
+	sDeviceKey *mdoc.PrivateKey,
+	rootCertificates []*x509.Certificate,
+	policy *mdoc.VerifierPolicy,
+	consent ConsentFunc,
+	options *session.SessionEncryptionOptions,
+) (*Session, error) {
+	if policy == nil {
+		policy = mdoc.DefaultVerifierPolicy()
+	}
+	if options == nil {
+		options = new(session.SessionEncryptionOptions)
+	}
+
+	sessionOptions := *options
+	if sessionOptions.CipherSuite == nil {
+		sessionOptions.CipherSuite = session.CipherSuiteAES256GCM
+	}
+
+	eDeviceKey, err := cipher_suite.GeneratePrivateKey(rand, curve, false)
+	if err != nil {
+		return nil, err
+	}
+
+	eDeviceKeyBytes, err := mdoccbor.MarshalToNewTaggedEncodedCBOR(&eDeviceKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceEngagement := &mdoc.DeviceEngagement{
+		Version: mdoc.DeviceEngagementVersion,
+		Security: mdoc.Security{
+			CipherSuiteIdentifier: sessionOptions.CipherSuite.Identifier(),
+			EDeviceKeyBytes:       *eDeviceKeyBytes,
+		},
+		DeviceRetrievalMethods: deviceRetrievalMethods,
+	}
+
+	deviceEngagementBytes, err := mdoccbor.MarshalToNewTaggedEncodedCBOR(deviceEngagement)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		rand:                  rand,
+		issuerSigneds:         issuerSigneds,
+		sDeviceKey:            sDeviceKey,
+		rootCertificates:      rootCertificates,
+		policy:                policy,
+		consent:               consent,
+		options:               sessionOptions,
+		state:                 SessionStateEngaged,
+		eDeviceKey:            eDeviceKey,
+		deviceEngagement:      deviceEngagement,
+		deviceEngagementBytes: deviceEngagementBytes,
+		handover:              handover,
+	}, nil
+}
+
+func (s *Session) State() SessionState {
+	return s.state
+}
+
+func (s *Session) DeviceEngagement() *mdoc.DeviceEngagement {
+	return s.deviceEngagement
+}
+
+// DeviceEngagementBytes returns the encoded DeviceEngagement to present to the reader.
+func (s *Session) DeviceEngagementBytes() []byte {
+	return s.deviceEngagementBytes.UntaggedValue
+}
+
+func (s *Session) SessionTranscript() *mdoc.SessionTranscript {
+	return s.sessionTranscript
+}
+
+// HandleSessionEstablishment establishes the session from the first message received from
+// the reader, and returns the SessionData containing the encrypted DeviceResponse.
+// When establishment or the response fails the session is terminated, unless the request
+// failed to decrypt and RetainCounterOnFailure is set, and session.StatusForError gives
+// the status to send to the reader.
+func (s *Session) HandleSessionEstablishment(
+	sessionEstablishment *session.SessionEstablishment,
+	now time.Time,
+) (*session.SessionData, error) {
+	if s.state != SessionStateEngaged {
+		return nil, ErrUnexpectedSessionState
+	}
+
+	eReaderKey, err := sessionEstablishment.EReaderKey()
+	if err != nil {
+		s.terminate()
+		return nil, session.ErrCBORDecoding
+	}
+
+	sessionTranscript := &mdoc.SessionTranscript{
+		DeviceEngagementBytes: s.deviceEngagementBytes,
+		EReaderKeyBytes:       &sessionEstablishment.EReaderKeyBytes,
+		Handover:              s.handover,
+	}
+
+	sessionTranscriptBytes, err := mdoc.NewSessionTranscriptBytes(sessionTranscript)
+	if err != nil {
+		s.terminate()
+		return nil, err
+	}
+
+	sessionEncryption, err := NewSessionEncryption(s.eDeviceKey, eReaderKey, sessionTranscriptBytes, &s.options)
+	if err != nil {
+		s.terminate()
+		return nil, session.ErrSessionEncryption
+	}
+
+	s.state = SessionStateEstablished
+	s.sessionTranscript = sessionTranscript
+	s.sessionEncryption = sessionEncryption
+
+	return s.respond(sessionEstablishment.Data, now)
+}
+
+// HandleSessionData responds to a subsequent request from the reader.
+// Any status received from the reader terminates the session, and is returned as an error.
+// When the response fails the session is terminated, unless the request failed to
+// decrypt and RetainCounterOnFailure is set, and session.StatusForError gives the status
+// to send to the reader.
+func (s *Session) HandleSessionData(sessionData *session.SessionData, now time.Time) (*session.SessionData, error) {
+	if s.state != SessionStateEstablished {
+		return nil, ErrUnexpectedSessionState
+	}
+
+	if sessionData.Status != 0 {
+		s.terminate()
+		return nil, sessionData.Status.Err()
+	}
+
+	if sessionData.Data == nil {
+		s.terminate()
+		return nil, ErrMissingSessionData
+	}
+
+	return s.respond(sessionData.Data, now)
+}
+
+// Terminate ends the session, returning the SessionData to send to the reader.
+func (s *Session) Terminate() *session.SessionData {
+	s.terminate()
+	return &session.SessionData{Status: session.SessionStatusSessionTermination}
+}
+
+func (s *Session) terminate() {
+	s.state = SessionStateTerminated
+	s.sessionEncryption = nil
+}
+
+// respond handles an encrypted DeviceRequest, terminating the session if it fails. A
+// request which fails to decrypt leaves the session established when the decryption
+// counter is retained, so that it may be resent.
+func (s *Session) respond(data []byte, now time.Time) (*session.SessionData, error) {
+	deviceRequestBytes, err := s.sessionEncryption.Decrypt(data)
+	if err != nil {
+		if !s.options.RetainCounterOnFailure || !errors.Is(err, session.ErrSessionEncryption) {
+			s.terminate()
+		}
+		return nil, err
+	}
+
+	sessionData, err := s.response(deviceRequestBytes, now)
+	if err != nil {
+		s.terminate()
+		return nil, err
+	}
+	return sessionData, nil
+}
+
+func (s *Session) response(deviceRequestBytes []byte, now time.Time) (*session.SessionData, error) {
+	deviceRequest, err := mdoc.DecodeDeviceRequest(deviceRequestBytes, s.options.DecodeLimits)
+	if err != nil {
+		return nil, session.ErrCBORDecoding
+	}
+
+	if deviceRequest.Version != mdoc.DeviceRequestVersion {
+		return nil, mdoc.ErrDeviceRequestUnsupportedVersion
+	}
+
+	approvedDocRequests := make([]mdoc.DocRequest, 0, len(deviceRequest.DocRequests))
+	var deniedDocumentErrors []mdoc.DocumentError
+	for _, docRequest := range deviceRequest.DocRequests {
+		itemsRequest, err := docRequest.ItemsRequest()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := s.issuerSigneds[itemsRequest.DocType]; !ok {
+			approvedDocRequests = append(approvedDocRequests, docRequest)
+			continue
+		}
+
+		readerCertificate, err := s.verifyReaderAuth(docRequest, now)
+		if err != nil {
+			return nil, err
+		}
+
+		approvedNameSpaces, err := s.consent(itemsRequest.DocType, itemsRequest.NameSpaces, readerCertificate)
+		if err != nil {
+			return nil, err
+		}
+
+		approvedNameSpaces = approvedNameSpaces.Filter(itemsRequest.NameSpaces.Contains)
+		if len(approvedNameSpaces) == 0 {
+			deniedDocumentErrors = append(deniedDocumentErrors, mdoc.DocumentError{
+				itemsRequest.DocType: mdoc.ErrorCodeDataNotReturned,
+			})
+			continue
+		}
+
+		approvedDocRequest, err := mdoc.NewDocRequest(&mdoc.ItemsRequest{
+			DocType:     itemsRequest.DocType,
+			NameSpaces:  approvedNameSpaces,
+			RequestInfo: itemsRequest.RequestInfo,
+		})
+		if err != nil {
+			return nil, err
+		}
+		approvedDocRequests = append(approvedDocRequests, *approvedDocRequest)
+	}
+
+	deviceResponse, err := NewDeviceResponse(
+		mdoc.NewDeviceRequest(approvedDocRequests),
+		s.issuerSigneds,
+		nil,
+		s.rand,
+		s.sDeviceKey,
+		s.sessionTranscript,
+	)
+	if err != nil {
+		return nil, err
+	}
+	deviceResponse.DocumentErrors = append(deviceResponse.DocumentErrors, deniedDocumentErrors...)
+
+	deviceResponseBytes, err := mdoccbor.Marshal(deviceResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	cipherText, err := s.sessionEncryption.Encrypt(deviceResponseBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session.SessionData{Data: cipherText}, nil
+}
+
+func (s *Session) verifyReaderAuth(docRequest mdoc.DocRequest, now time.Time) (*x509.Certificate, error) {
+	if err := docRequest.VerifyWithPolicy(s.policy, s.rootCertificates, now, s.sessionTranscript); err != nil {
+		return nil, err
+	}
+
+	if docRequest.ReaderAuth == nil {
+		return nil, nil
+	}
+
+	chain, err := cose2.X509Chain(docRequest.ReaderAuth.Headers.Unprotected)
+	if err != nil {
+		return nil, err
+	}
+
+	return chain[len(chain)-1], nil
+}
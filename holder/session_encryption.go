@@ -10,6 +10,7 @@ func NewSessionEncryption(
 	eDeviceKey *mdoc.PrivateKey,
 	eReaderKey *mdoc.PublicKey,
 	sessionTranscriptBytes *cbor.TaggedEncodedCBOR,
+	options *session.SessionEncryptionOptions,
 ) (*session.SessionEncryption, error) {
 	skDevice, err := session.SKDevice(eDeviceKey.Agreer, eReaderKey, sessionTranscriptBytes.TaggedValue)
 	if err != nil {
@@ -26,5 +27,6 @@ func NewSessionEncryption(
 		session.DeviceIdentifier,
 		skReader,
 		session.ReaderIdentifier,
+		options,
 	)
 }
@@ -0,0 +1,313 @@
+// Package brainpool implements the Brainpool r1 curves of RFC 5639 as elliptic.Curve.
+//
+// The curves are for use with the legacy, math/big backed, verification path of
+// crypto/ecdsa and for handling public keys. The arithmetic is not constant time, so it
+// must not be used with secret scalars: no signing, key generation or key agreement.
+package brainpool
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"sync"
+)
+
+// Curve is a short Weierstrass curve y² = x³ + ax + b over a prime field. Unlike
+// elliptic.CurveParams, it supports any a, as required by the r1 curves.
+type Curve struct {
+	params *elliptic.CurveParams
+	a      *big.Int
+}
+
+var (
+	initOnce sync.Once
+	p256r1   *Curve
+	p320r1   *Curve
+	p384r1   *Curve
+	p512r1   *Curve
+)
+
+func initCurves() {
+	p256r1 = newCurve(
+		"brainpoolP256r1",
+		256,
+		"a9fb57dba1eea9bc3e660a909d838d726e3bf623d52620282013481d1f6e5377",
+		"7d5a0975fc2c3057eef67530417affe7fb8055c126dc5c6ce94a4b44f330b5d9",
+		"26dc5c6ce94a4b44f330b5d9bbd77cbf958416295cf7e1ce6bccdc18ff8c07b6",
+		"8bd2aeb9cb7e57cb2c4b482ffc81b7afb9de27e1e3bd23c23a4453bd9ace3262",
+		"547ef835c3dac4fd97f8461a14611dc9c27745132ded8e545c1d54c72f046997",
+		"a9fb57dba1eea9bc3e660a909d838d718c397aa3b561a6f7901e0e82974856a7",
+	)
+	p320r1 = newCurve(
+		"brainpoolP320r1",
+		320,
+		"d35e472036bc4fb7e13c785ed201e065f98fcfa6f6f40def4f92b9ec7893ec28fcd412b1f1b32e27",
+		"3ee30b568fbab0f883ccebd46d3f3bb8a2a73513f5eb79da66190eb085ffa9f492f375a97d860eb4",
+		"520883949dfdbc42d3ad198640688a6fe13f41349554b49acc31dccd884539816f5eb4ac8fb1f1a6",
+		"43bd7e9afb53d8b85289bcc48ee5bfe6f20137d10a087eb6e7871e2a10a599c710af8d0d39e20611",
+		"14fdd05545ec1cc8ab4093247f77275e0743ffed117182eaa9c77877aaac6ac7d35245d1692e8ee1",
+		"d35e472036bc4fb7e13c785ed201e065f98fcfa5b68f12a32d482ec7ee8658e98691555b44c59311",
+	)
+	p384r1 = newCurve(
+		"brainpoolP384r1",
+		384,
+		"8cb91e82a3386d280f5d6f7e50e641df152f7109ed5456b412b1da197fb71123acd3a729901d1a71874700133107ec53",
+		"7bc382c63d8c150c3c72080ace05afa0c2bea28e4fb22787139165efba91f90f8aa5814a503ad4eb04a8c7dd22ce2826",
+		"04a8c7dd22ce28268b39b55416f0447c2fb77de107dcd2a62e880ea53eeb62d57cb4390295dbc9943ab78696fa504c11",
+		"1d1c64f068cf45ffa2a63a81b7c13f6b8847a3e77ef14fe3db7fcafe0cbd10e8e826e03436d646aaef87b2e247d4af1e",
+		"8abe1d7520f9c2a45cb1eb8e95cfd55262b70b29feec5864e19c054ff99129280e4646217791811142820341263c5315",
+		"8cb91e82a3386d280f5d6f7e50e641df152f7109ed5456b31f166e6cac0425a7cf3ab6af6b7fc3103b883202e9046565",
+	)
+	p512r1 = newCurve(
+		"brainpoolP512r1",
+		512,
+		"aadd9db8dbe9c48b3fd4e6ae33c9fc07cb308db3b3c9d20ed6639cca703308717d4d9b009bc66842aecda12ae6a380e62881ff2f2d82c68528aa6056583a48f3",
+		"7830a3318b603b89e2327145ac234cc594cbdd8d3df91610a83441caea9863bc2ded5d5aa8253aa10a2ef1c98b9ac8b57f1117a72bf2c7b9e7c1ac4d77fc94ca",
+		"3df91610a83441caea9863bc2ded5d5aa8253aa10a2ef1c98b9ac8b57f1117a72bf2c7b9e7c1ac4d77fc94cadc083e67984050b75ebae5dd2809bd638016f723",
+		"81aee4bdd82ed9645a21322e9c4c6a9385ed9f70b5d916c1b43b62eef4d0098eff3b1f78e2d0d48d50d1687b93b97d5f7c6d5047406a5e688b352209bcb9f822",
+		"7dde385d566332ecc0eabfa9cf7822fdf209f70024a57b1aa000c55b881f8111b2dcde494a5f485e5bca4bd88a2763aed1ca2b2fa8f0540678cd1e0f3ad80892",
+		"aadd9db8dbe9c48b3fd4e6ae33c9fc07cb308db3b3c9d20ed6639cca70330870553e5c414ca92619418661197fac10471db1d381085ddaddb58796829ca90069",
+	)
+}
+
+func newCurve(name string, bitSize int, p, a, b, x, y, n string) *Curve {
+	return &Curve{
+		params: &elliptic.CurveParams{
+			Name:    name,
+			BitSize: bitSize,
+			P:       hexInt(p),
+			N:       hexInt(n),
+			B:       hexInt(b),
+			Gx:      hexInt(x),
+			Gy:      hexInt(y),
+		},
+		a: hexInt(a),
+	}
+}
+
+func hexInt(s string) *big.Int {
+	i, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("brainpool: invalid constant")
+	}
+	return i
+}
+
+// P256r1 returns brainpoolP256r1.
+func P256r1() elliptic.Curve {
+	initOnce.Do(initCurves)
+	return p256r1
+}
+
+// P320r1 returns brainpoolP320r1.
+func P320r1() elliptic.Curve {
+	initOnce.Do(initCurves)
+	return p320r1
+}
+
+// P384r1 returns brainpoolP384r1.
+func P384r1() elliptic.Curve {
+	initOnce.Do(initCurves)
+	return p384r1
+}
+
+// P512r1 returns brainpoolP512r1.
+func P512r1() elliptic.Curve {
+	initOnce.Do(initCurves)
+	return p512r1
+}
+
+// Params returns the curve parameters. The generic arithmetic of elliptic.CurveParams
+// assumes a = -3, so must not be used with the returned value.
+func (c *Curve) Params() *elliptic.CurveParams {
+	return c.params
+}
+
+// A returns the a coefficient of the curve equation.
+func (c *Curve) A() *big.Int {
+	return new(big.Int).Set(c.a)
+}
+
+func (c *Curve) IsOnCurve(x, y *big.Int) bool {
+	p := c.params.P
+	if x.Sign() < 0 || x.Cmp(p) >= 0 || y.Sign() < 0 || y.Cmp(p) >= 0 {
+		return false
+	}
+
+	// y² = x³ + ax + b
+	y2 := new(big.Int).Mul(y, y)
+	y2.Mod(y2, p)
+
+	rhs := new(big.Int).Mul(x, x)
+	rhs.Add(rhs, c.a)
+	rhs.Mul(rhs, x)
+	rhs.Add(rhs, c.params.B)
+	rhs.Mod(rhs, p)
+
+	return y2.Cmp(rhs) == 0
+}
+
+// jacobian is a point in Jacobian coordinates, the point at infinity having z = 0.
+type jacobian struct {
+	x, y, z *big.Int
+}
+
+func (c *Curve) toJacobian(x, y *big.Int) *jacobian {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return &jacobian{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	return &jacobian{new(big.Int).Set(x), new(big.Int).Set(y), big.NewInt(1)}
+}
+
+func (c *Curve) toAffine(point *jacobian) (*big.Int, *big.Int) {
+	if point.z.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+
+	p := c.params.P
+	zInv := new(big.Int).ModInverse(point.z, p)
+	zInv2 := new(big.Int).Mul(zInv, zInv)
+
+	x := new(big.Int).Mul(point.x, zInv2)
+	x.Mod(x, p)
+
+	y := zInv2.Mul(zInv2, zInv)
+	y.Mul(y, point.y)
+	y.Mod(y, p)
+
+	return x, y
+}
+
+func (c *Curve) double(point *jacobian) *jacobian {
+	p := c.params.P
+	if point.z.Sign() == 0 || point.y.Sign() == 0 {
+		return &jacobian{new(big.Int), new(big.Int), new(big.Int)}
+	}
+
+	xx := new(big.Int).Mul(point.x, point.x)
+	yy := new(big.Int).Mul(point.y, point.y)
+	yy.Mod(yy, p)
+	yyyy := new(big.Int).Mul(yy, yy)
+	zz := new(big.Int).Mul(point.z, point.z)
+	zz.Mod(zz, p)
+
+	// s = 4xy²
+	s := new(big.Int).Mul(point.x, yy)
+	s.Lsh(s, 2)
+	s.Mod(s, p)
+
+	// m = 3x² + az⁴
+	m := new(big.Int).Mul(zz, zz)
+	m.Mul(m, c.a)
+	m.Add(m, xx.Mul(xx, big.NewInt(3)))
+	m.Mod(m, p)
+
+	// x3 = m² - 2s
+	x3 := new(big.Int).Mul(m, m)
+	x3.Sub(x3, new(big.Int).Lsh(s, 1))
+	x3.Mod(x3, p)
+
+	// y3 = m(s - x3) - 8y⁴
+	y3 := new(big.Int).Sub(s, x3)
+	y3.Mul(y3, m)
+	y3.Sub(y3, yyyy.Lsh(yyyy, 3))
+	y3.Mod(y3, p)
+
+	// z3 = 2yz
+	z3 := new(big.Int).Mul(point.y, point.z)
+	z3.Lsh(z3, 1)
+	z3.Mod(z3, p)
+
+	return &jacobian{x3, y3, z3}
+}
+
+func (c *Curve) add(a, b *jacobian) *jacobian {
+	if a.z.Sign() == 0 {
+		return b
+	}
+	if b.z.Sign() == 0 {
+		return a
+	}
+
+	p := c.params.P
+
+	z1z1 := new(big.Int).Mul(a.z, a.z)
+	z1z1.Mod(z1z1, p)
+	z2z2 := new(big.Int).Mul(b.z, b.z)
+	z2z2.Mod(z2z2, p)
+
+	u1 := new(big.Int).Mul(a.x, z2z2)
+	u1.Mod(u1, p)
+	u2 := new(big.Int).Mul(b.x, z1z1)
+	u2.Mod(u2, p)
+
+	s1 := new(big.Int).Mul(a.y, b.z)
+	s1.Mul(s1, z2z2)
+	s1.Mod(s1, p)
+	s2 := new(big.Int).Mul(b.y, a.z)
+	s2.Mul(s2, z1z1)
+	s2.Mod(s2, p)
+
+	if u1.Cmp(u2) == 0 {
+		if s1.Cmp(s2) != 0 {
+			return &jacobian{new(big.Int), new(big.Int), new(big.Int)}
+		}
+		return c.double(a)
+	}
+
+	h := new(big.Int).Sub(u2, u1)
+	r := new(big.Int).Sub(s2, s1)
+
+	hh := new(big.Int).Mul(h, h)
+	hh.Mod(hh, p)
+	hhh := new(big.Int).Mul(h, hh)
+	hhh.Mod(hhh, p)
+	v := new(big.Int).Mul(u1, hh)
+	v.Mod(v, p)
+
+	// x3 = r² - h³ - 2v
+	x3 := new(big.Int).Mul(r, r)
+	x3.Sub(x3, hhh)
+	x3.Sub(x3, new(big.Int).Lsh(v, 1))
+	x3.Mod(x3, p)
+
+	// y3 = r(v - x3) - s1h³
+	y3 := new(big.Int).Sub(v, x3)
+	y3.Mul(y3, r)
+	y3.Sub(y3, s1.Mul(s1, hhh))
+	y3.Mod(y3, p)
+
+	// z3 = z1z2h
+	z3 := new(big.Int).Mul(a.z, b.z)
+	z3.Mul(z3, h)
+	z3.Mod(z3, p)
+
+	return &jacobian{x3, y3, z3}
+}
+
+func (c *Curve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	return c.toAffine(c.add(c.toJacobian(x1, y1), c.toJacobian(x2, y2)))
+}
+
+func (c *Curve) Double(x, y *big.Int) (*big.Int, *big.Int) {
+	return c.toAffine(c.double(c.toJacobian(x, y)))
+}
+
+func (c *Curve) ScalarMult(x, y *big.Int, k []byte) (*big.Int, *big.Int) {
+	base := c.toJacobian(x, y)
+	result := &jacobian{new(big.Int), new(big.Int), new(big.Int)}
+
+	for _, b := range k {
+		for bit := 7; bit >= 0; bit-- {
+			result = c.double(result)
+			if b>>bit&1 == 1 {
+				result = c.add(result, base)
+			}
+		}
+	}
+
+	return c.toAffine(result)
+}
+
+func (c *Curve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	return c.ScalarMult(c.params.Gx, c.params.Gy, k)
+}
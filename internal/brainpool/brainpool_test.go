@@ -0,0 +1,154 @@
+package brainpool
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/alex-richards/go-mdoc/internal/testutil"
+)
+
+var curves = []struct {
+	name  string
+	curve elliptic.Curve
+}{
+	{name: "brainpoolP256r1", curve: P256r1()},
+	{name: "brainpoolP320r1", curve: P320r1()},
+	{name: "brainpoolP384r1", curve: P384r1()},
+	{name: "brainpoolP512r1", curve: P512r1()},
+}
+
+func Test_Curve_Generator(t *testing.T) {
+	for _, tt := range curves {
+		t.Run(tt.name, func(t *testing.T) {
+			params := tt.curve.Params()
+			if params.Name != tt.name {
+				t.Fatalf("expected %s, got %s", tt.name, params.Name)
+			}
+			if !tt.curve.IsOnCurve(params.Gx, params.Gy) {
+				t.Fatal("generator not on curve")
+			}
+
+			x, y := tt.curve.ScalarBaseMult(params.N.Bytes())
+			if x.Sign() != 0 || y.Sign() != 0 {
+				t.Fatal("expected n * G to be the point at infinity")
+			}
+
+			x, y = tt.curve.Double(params.Gx, params.Gy)
+			x2, y2 := tt.curve.Add(params.Gx, params.Gy, params.Gx, params.Gy)
+			x3, y3 := tt.curve.ScalarBaseMult([]byte{2})
+			if x.Cmp(x2) != 0 || y.Cmp(y2) != 0 || x.Cmp(x3) != 0 || y.Cmp(y3) != 0 {
+				t.Fatal("expected 2G = G + G")
+			}
+			if !tt.curve.IsOnCurve(x, y) {
+				t.Fatal("2G not on curve")
+			}
+		})
+	}
+}
+
+// Test_Curve_ECDH checks the ECDH test vectors of RFC 7027 appendix A.
+func Test_Curve_ECDH(t *testing.T) {
+	tests := []struct {
+		name   string
+		curve  elliptic.Curve
+		dA     string
+		xA, yA string
+		dB     string
+		xB, yB string
+		xZ, yZ string
+	}{
+		{
+			name:  "brainpoolP256r1",
+			curve: P256r1(),
+			dA:    "81db1ee100150ff2ea338d708271be38300cb54241d79950f77b063039804f1d",
+			xA:    "44106e913f92bc02a1705d9953a8414db95e1aaa49e81d9e85f929a8e3100be5",
+			yA:    "8ab4846f11caccb73ce49cbdd120f5a900a69fd32c272223f789ef10eb089bdc",
+			dB:    "55e40bc41e37e3e2ad25c3c6654511ffa8474a91a0032087593852d3e7d76bd3",
+			xB:    "8d2d688c6cf93e1160ad04cc4429117dc2c41825e1e9fca0addd34e6f1b39f7b",
+			yB:    "990c57520812be512641e47034832106bc7d3e8dd0e4c7f1136d7006547cec6a",
+			xZ:    "89afc39d41d3b327814b80940b042590f96556ec91e6ae7939bce31f3a18bf2b",
+			yZ:    "49c27868f4eca2179bfd7d59b1e3bf34c1dbde61ae12931648f43e59632504de",
+		},
+		{
+			name:  "brainpoolP384r1",
+			curve: P384r1(),
+			dA:    "1e20f5e048a5886f1f157c74e91bde2b98c8b52d58e5003d57053fc4b0bd65d6f15eb5d1ee1610df870795143627d042",
+			xA:    "68b665dd91c195800650cdd363c625f4e742e8134667b767b1b476793588f885ab698c852d4a6e77a252d6380fcaf068",
+			yA:    "55bc91a39c9ec01dee36017b7d673a931236d2f1f5c83942d049e3fa20607493e0d038ff2fd30c2ab67d15c85f7faa59",
+			dB:    "032640bc6003c59260f7250c3db58ce647f98e1260acce4acda3dd869f74e01f8ba5e0324309db6a9831497abac96670",
+			xB:    "4d44326f269a597a5b58bba565da5556ed7fd9a8a9eb76c25f46db69d19dc8ce6ad18e404b15738b2086df37e71d1eb4",
+			yB:    "62d692136de56cbe93bf5fa3188ef58bc8a3a0ec6c1e151a21038a42e9185329b5b275903d192f8d4e1f32fe9cc78c48",
+			xZ:    "0bd9d3a7ea0b3d519d09d8e48d0785fb744a6b355e6304bc51c229fbbce239bbadf6403715c35d4fb2a5444f575d4f42",
+			yZ:    "0df213417ebe4d8e40a5f76f66c56470c489a3478d146decf6df0d94bae9e598157290f8756066975f1db34b2324b7bd",
+		},
+		{
+			name:  "brainpoolP512r1",
+			curve: P512r1(),
+			dA:    "16302ff0dbbb5a8d733dab7141c1b45acbc8715939677f6a56850a38bd87bd59b09e80279609ff333eb9d4c061231fb26f92eeb04982a5f1d1764cad57665422",
+			xA:    "0a420517e406aac0acdce90fcd71487718d3b953efd7fbec5f7f27e28c6149999397e91e029e06457db2d3e640668b392c2a7e737a7f0bf04436d11640fd09fd",
+			yA:    "72e6882e8db28aad36237cd25d580db23783961c8dc52dfa2ec138ad472a0fcef3887cf62b623b2a87de5c588301ea3e5fc269b373b60724f5e82a6ad147fde7",
+			dB:    "230e18e1bcc88a362fa54e4ea3902009292f7f8033624fd471b5d8ace49d12cfabbc19963dab8e2f1eba00bffb29e4d72d13f2224562f405cb80503666b25429",
+			xB:    "9d45f66de5d67e2e6db6e93a59ce0bb48106097ff78a081de781cdb31fce8ccbaaea8dd4320c4119f1e9cd437a2eab3731fa9668ab268d871deda55a5473199f",
+			yB:    "2fdc313095bcdd5fb3a91636f07a959c8e86b5636a1e930e8396049cb481961d365cc11453a06c719835475b12cb52fc3c383bce35e27ef194512b71876285fa",
+			xZ:    "a7927098655f1f9976fa50a9d566865dc530331846381c87256baf3226244b76d36403c024d7bbf0aa0803eaff405d3d24f11a9b5c0bef679fe1454b21c4cd1f",
+			yZ:    "7db71c3def63212841c463e881bdcf055523bd368240e6c3143bd8def8b3b3223b95e0f53082ff5e412f4222537a43df1c6d25729ddb51620a832be6a26680a2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dA := hexInt(tt.dA)
+			dB := hexInt(tt.dB)
+
+			check := func(name string, x, y *big.Int, wantX, wantY string) {
+				t.Helper()
+				if x.Cmp(hexInt(wantX)) != 0 || y.Cmp(hexInt(wantY)) != 0 {
+					t.Fatalf("%s: expected (%s, %s), got (%x, %x)", name, wantX, wantY, x, y)
+				}
+			}
+
+			xA, yA := tt.curve.ScalarBaseMult(dA.Bytes())
+			check("qA", xA, yA, tt.xA, tt.yA)
+
+			xB, yB := tt.curve.ScalarBaseMult(dB.Bytes())
+			check("qB", xB, yB, tt.xB, tt.yB)
+
+			xZ, yZ := tt.curve.ScalarMult(xB, yB, dA.Bytes())
+			check("Z", xZ, yZ, tt.xZ, tt.yZ)
+
+			xZ, yZ = tt.curve.ScalarMult(xA, yA, dB.Bytes())
+			check("Z", xZ, yZ, tt.xZ, tt.yZ)
+		})
+	}
+}
+
+func Test_Curve_ECDSA(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+	digest := sha256.Sum256([]byte("message"))
+
+	for _, tt := range curves {
+		t.Run(tt.name, func(t *testing.T) {
+			privateKey, err := ecdsa.GenerateKey(tt.curve, rand)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			signature, err := ecdsa.SignASN1(rand, privateKey, digest[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !ecdsa.VerifyASN1(&privateKey.PublicKey, digest[:], signature) {
+				t.Fatal("signature not verified")
+			}
+
+			digest2 := sha256.Sum256([]byte("other message"))
+			if ecdsa.VerifyASN1(&privateKey.PublicKey, digest2[:], signature) {
+				t.Fatal("signature verified for other message")
+			}
+		})
+	}
+}
@@ -0,0 +1,349 @@
+package cbor
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+var (
+	ErrMalformed           = errors.New("mdoc: cbor: malformed data")
+	ErrExtraneousData      = errors.New("mdoc: cbor: extraneous data")
+	ErrMaxNestedLevels     = errors.New("mdoc: cbor: exceeded max nested levels")
+	ErrMaxArrayElements    = errors.New("mdoc: cbor: exceeded max array elements")
+	ErrMaxMapPairs         = errors.New("mdoc: cbor: exceeded max map pairs")
+	ErrMaxByteStringLength = errors.New("mdoc: cbor: exceeded max byte string length")
+	ErrIndefiniteLength    = errors.New("mdoc: cbor: indefinite length not allowed")
+	ErrDuplicateMapKey     = errors.New("mdoc: cbor: duplicate map key")
+)
+
+const (
+	DefaultMaxNestedLevels     = 32
+	DefaultMaxArrayElements    = 65536
+	DefaultMaxMapPairs         = 65536
+	DefaultMaxByteStringLength = 4 << 20
+
+	// upper bounds accepted by cbor.DecOptions
+	maxNestedLevels  = 65535
+	maxArrayElements = 2147483647
+	maxMapPairs      = 2147483647
+)
+
+// Limits bound the resources used decoding untrusted data. Zero valued limits use the
+// defaults. Byte strings tagged as encoded CBOR (tag 24) are checked as nested items.
+type Limits struct {
+	// MaxNestedLevels is the maximum depth of arrays, maps and tags.
+	MaxNestedLevels int
+	// MaxArrayElements is the maximum number of elements in an array.
+	MaxArrayElements int
+	// MaxMapPairs is the maximum number of key value pairs in a map.
+	MaxMapPairs int
+	// MaxByteStringLength is the maximum length of a byte or text string.
+	MaxByteStringLength int
+	// AllowDuplicateMapKeys accepts maps with repeated keys.
+	AllowDuplicateMapKeys bool
+	// AllowIndefiniteLength accepts indefinite length strings, arrays and maps.
+	AllowIndefiniteLength bool
+}
+
+func (l Limits) withDefaults() Limits {
+	if l.MaxNestedLevels <= 0 {
+		l.MaxNestedLevels = DefaultMaxNestedLevels
+	}
+	if l.MaxArrayElements <= 0 {
+		l.MaxArrayElements = DefaultMaxArrayElements
+	}
+	if l.MaxMapPairs <= 0 {
+		l.MaxMapPairs = DefaultMaxMapPairs
+	}
+	if l.MaxByteStringLength <= 0 {
+		l.MaxByteStringLength = DefaultMaxByteStringLength
+	}
+	return l
+}
+
+// decodeModeKey holds the Limits that change how data is decoded. The size limits are
+// enforced by Check before decoding, so the decode modes only need to permit the largest
+// values, and at most four are ever cached.
+type decodeModeKey struct {
+	allowDuplicateMapKeys bool
+	allowIndefiniteLength bool
+}
+
+var limitsDecodeModes sync.Map // decodeModeKey -> cbor.DecMode
+
+func (l Limits) decodeMode() (cbor.DecMode, error) {
+	key := decodeModeKey{
+		allowDuplicateMapKeys: l.AllowDuplicateMapKeys,
+		allowIndefiniteLength: l.AllowIndefiniteLength,
+	}
+	if decMode, ok := limitsDecodeModes.Load(key); ok {
+		return decMode.(cbor.DecMode), nil
+	}
+
+	decOptions := DecOptions()
+	decOptions.MaxNestedLevels = maxNestedLevels
+	decOptions.MaxArrayElements = maxArrayElements
+	decOptions.MaxMapPairs = maxMapPairs
+	if key.allowDuplicateMapKeys {
+		decOptions.DupMapKey = cbor.DupMapKeyQuiet
+	}
+	if !key.allowIndefiniteLength {
+		decOptions.IndefLength = cbor.IndefLengthForbidden
+	}
+
+	decMode, err := decOptions.DecMode()
+	if err != nil {
+		return nil, err
+	}
+
+	limitsDecodeModes.Store(key, decMode)
+	return decMode, nil
+}
+
+// UnmarshalWithLimits checks data against limits before decoding it into value.
+func UnmarshalWithLimits(data []byte, value any, limits Limits) error {
+	limits = limits.withDefaults()
+
+	if err := limits.Check(data); err != nil {
+		return err
+	}
+
+	decMode, err := limits.decodeMode()
+	if err != nil {
+		return err
+	}
+	return decMode.Unmarshal(data, value)
+}
+
+// Check walks a single encoded item, returning an error if it exceeds the limits.
+func (l Limits) Check(data []byte) error {
+	l = l.withDefaults()
+
+	c := limitsChecker{limits: &l, data: data}
+	if err := c.item(1); err != nil {
+		return err
+	}
+	if c.offset != len(data) {
+		return ErrExtraneousData
+	}
+	return nil
+}
+
+const (
+	majorTypeUint   = 0
+	majorTypeNegInt = 1
+	majorTypeBstr   = 2
+	majorTypeTstr   = 3
+	majorTypeArray  = 4
+	majorTypeMap    = 5
+	majorTypeTag    = 6
+	majorTypeSimple = 7
+
+	additionalInformationUint8  = 24
+	additionalInformationUint64 = 27
+	additionalInformationIndef  = 31
+
+	breakCode = majorTypeSimple<<5 | additionalInformationIndef
+)
+
+type limitsChecker struct {
+	limits *Limits
+	data   []byte
+	offset int
+}
+
+// head reads the initial byte and argument of an item. indefinite is set for
+// additional information 31, which is only valid for strings, arrays and maps.
+func (c *limitsChecker) head() (majorType byte, argument uint64, indefinite bool, err error) {
+	if c.offset >= len(c.data) {
+		return 0, 0, false, ErrMalformed
+	}
+
+	initial := c.data[c.offset]
+	majorType = initial >> 5
+	additionalInformation := initial & 0x1f
+	c.offset++
+
+	switch {
+	case additionalInformation < additionalInformationUint8:
+		return majorType, uint64(additionalInformation), false, nil
+	case additionalInformation <= additionalInformationUint64:
+		length := 1 << (additionalInformation - additionalInformationUint8)
+		if len(c.data)-c.offset < length {
+			return 0, 0, false, ErrMalformed
+		}
+		for _, b := range c.data[c.offset : c.offset+length] {
+			argument = argument<<8 | uint64(b)
+		}
+		c.offset += length
+		return majorType, argument, false, nil
+	case additionalInformation == additionalInformationIndef:
+		switch majorType {
+		case majorTypeBstr, majorTypeTstr, majorTypeArray, majorTypeMap:
+			if !c.limits.AllowIndefiniteLength {
+				return 0, 0, false, ErrIndefiniteLength
+			}
+			return majorType, 0, true, nil
+		}
+	}
+
+	return 0, 0, false, ErrMalformed
+}
+
+func (c *limitsChecker) isBreak() bool {
+	return c.offset < len(c.data) && c.data[c.offset] == breakCode
+}
+
+func (c *limitsChecker) content(length uint64) ([]byte, error) {
+	if length > uint64(c.limits.MaxByteStringLength) {
+		return nil, ErrMaxByteStringLength
+	}
+	if uint64(len(c.data)-c.offset) < length {
+		return nil, ErrMalformed
+	}
+	content := c.data[c.offset : c.offset+int(length)]
+	c.offset += int(length)
+	return content, nil
+}
+
+func (c *limitsChecker) item(level int) error {
+	majorType, argument, indefinite, err := c.head()
+	if err != nil {
+		return err
+	}
+
+	switch majorType {
+	case majorTypeBstr, majorTypeTstr:
+		if !indefinite {
+			_, err = c.content(argument)
+			return err
+		}
+		total := uint64(0)
+		for !c.isBreak() {
+			chunkMajorType, chunkLength, chunkIndefinite, err := c.head()
+			if err != nil {
+				return err
+			}
+			if chunkMajorType != majorType || chunkIndefinite {
+				return ErrMalformed
+			}
+			total += chunkLength
+			if _, err = c.content(chunkLength); err != nil {
+				return err
+			}
+			if total > uint64(c.limits.MaxByteStringLength) {
+				return ErrMaxByteStringLength
+			}
+		}
+		return c.breakItem()
+
+	case majorTypeArray:
+		if level > c.limits.MaxNestedLevels {
+			return ErrMaxNestedLevels
+		}
+		if indefinite {
+			for count := 0; !c.isBreak(); count++ {
+				if count >= c.limits.MaxArrayElements {
+					return ErrMaxArrayElements
+				}
+				if err = c.item(level + 1); err != nil {
+					return err
+				}
+			}
+			return c.breakItem()
+		}
+		if argument > uint64(c.limits.MaxArrayElements) {
+			return ErrMaxArrayElements
+		}
+		for i := uint64(0); i < argument; i++ {
+			if err = c.item(level + 1); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case majorTypeMap:
+		if level > c.limits.MaxNestedLevels {
+			return ErrMaxNestedLevels
+		}
+		var keys map[string]struct{}
+		if !c.limits.AllowDuplicateMapKeys {
+			keys = make(map[string]struct{})
+		}
+		entry := func() error {
+			start := c.offset
+			if err := c.item(level + 1); err != nil {
+				return err
+			}
+			if keys != nil {
+				key := string(c.data[start:c.offset])
+				if _, ok := keys[key]; ok {
+					return ErrDuplicateMapKey
+				}
+				keys[key] = struct{}{}
+			}
+			return c.item(level + 1)
+		}
+		if indefinite {
+			for count := 0; !c.isBreak(); count++ {
+				if count >= c.limits.MaxMapPairs {
+					return ErrMaxMapPairs
+				}
+				if err = entry(); err != nil {
+					return err
+				}
+			}
+			return c.breakItem()
+		}
+		if argument > uint64(c.limits.MaxMapPairs) {
+			return ErrMaxMapPairs
+		}
+		for i := uint64(0); i < argument; i++ {
+			if err = entry(); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case majorTypeTag:
+		if level > c.limits.MaxNestedLevels {
+			return ErrMaxNestedLevels
+		}
+		if argument != TagEncodedCBOR {
+			return c.item(level + 1)
+		}
+
+		// check encoded CBOR as if it were nested in place
+		contentMajorType, length, contentIndefinite, err := c.head()
+		if err != nil {
+			return err
+		}
+		if contentMajorType != majorTypeBstr || contentIndefinite {
+			return ErrMalformed
+		}
+		content, err := c.content(length)
+		if err != nil {
+			return err
+		}
+		embedded := limitsChecker{limits: c.limits, data: content}
+		if err = embedded.item(level + 1); err != nil {
+			return err
+		}
+		if embedded.offset != len(content) {
+			return ErrExtraneousData
+		}
+		return nil
+	}
+
+	return nil
+}
+
+func (c *limitsChecker) breakItem() error {
+	if !c.isBreak() {
+		return ErrMalformed
+	}
+	c.offset++
+	return nil
+}
@@ -0,0 +1,123 @@
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func Test_Limits_Check(t *testing.T) {
+	nested := func(levels int) []byte {
+		data := bytes.Repeat([]byte{0x81}, levels)
+		return append(data, 0x00)
+	}
+	// array of n zeros
+	array := func(n int) []byte {
+		data := []byte{0x99, byte(n >> 8), byte(n)}
+		return append(data, make([]byte, n)...)
+	}
+	// map of n distinct uint keys to 0
+	intMap := func(n int) []byte {
+		data := []byte{0xb9, byte(n >> 8), byte(n)}
+		for i := 0; i < n; i++ {
+			data = append(data, 0x19, byte(i>>8), byte(i), 0x00)
+		}
+		return data
+	}
+
+	tests := []struct {
+		name    string
+		limits  Limits
+		data    []byte
+		wantErr error
+	}{
+		{name: "valid", data: []byte{0xa1, 0x61, 'a', 0x82, 0x01, 0x41, 0x02}},
+		{name: "nested levels", limits: Limits{MaxNestedLevels: 4}, data: nested(4)},
+		{name: "max nested levels", limits: Limits{MaxNestedLevels: 4}, data: nested(5), wantErr: ErrMaxNestedLevels},
+		{name: "default max nested levels", data: nested(DefaultMaxNestedLevels + 1), wantErr: ErrMaxNestedLevels},
+		{
+			name:    "max nested levels encoded CBOR",
+			limits:  Limits{MaxNestedLevels: 4},
+			data:    []byte{0x81, 0x81, 0x81, 0xd8, 0x18, 0x42, 0x81, 0x00},
+			wantErr: ErrMaxNestedLevels,
+		},
+		{name: "array elements", limits: Limits{MaxArrayElements: 16}, data: array(16)},
+		{name: "max array elements", limits: Limits{MaxArrayElements: 16}, data: array(17), wantErr: ErrMaxArrayElements},
+		{name: "map pairs", limits: Limits{MaxMapPairs: 16}, data: intMap(16)},
+		{name: "max map pairs", limits: Limits{MaxMapPairs: 16}, data: intMap(17), wantErr: ErrMaxMapPairs},
+		{name: "byte string length", limits: Limits{MaxByteStringLength: 2}, data: []byte{0x42, 0x01, 0x02}},
+		{name: "max byte string length", limits: Limits{MaxByteStringLength: 2}, data: []byte{0x43, 0x01, 0x02, 0x03}, wantErr: ErrMaxByteStringLength},
+		{name: "max text string length", limits: Limits{MaxByteStringLength: 2}, data: []byte{0x63, 'a', 'b', 'c'}, wantErr: ErrMaxByteStringLength},
+		{
+			name:    "huge byte string length",
+			data:    []byte{0x5b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			wantErr: ErrMaxByteStringLength,
+		},
+		{name: "duplicate map key", data: []byte{0xa2, 0x01, 0x00, 0x01, 0x01}, wantErr: ErrDuplicateMapKey},
+		{name: "allow duplicate map key", limits: Limits{AllowDuplicateMapKeys: true}, data: []byte{0xa2, 0x01, 0x00, 0x01, 0x01}},
+		{name: "indefinite length", data: []byte{0x9f, 0x00, 0xff}, wantErr: ErrIndefiniteLength},
+		{name: "allow indefinite length", limits: Limits{AllowIndefiniteLength: true}, data: []byte{0x9f, 0x00, 0xff}},
+		{
+			name:    "allow indefinite length max byte string length",
+			limits:  Limits{AllowIndefiniteLength: true, MaxByteStringLength: 2},
+			data:    []byte{0x5f, 0x42, 0x01, 0x02, 0x41, 0x03, 0xff},
+			wantErr: ErrMaxByteStringLength,
+		},
+		{name: "truncated", data: []byte{0x82, 0x00}, wantErr: ErrMalformed},
+		{name: "reserved additional information", data: []byte{0x1c}, wantErr: ErrMalformed},
+		{name: "unexpected break", data: []byte{0xff}, wantErr: ErrMalformed},
+		{name: "extraneous data", data: []byte{0x00, 0x00}, wantErr: ErrExtraneousData},
+		{name: "encoded CBOR extraneous data", data: []byte{0xd8, 0x18, 0x42, 0x00, 0x00}, wantErr: ErrExtraneousData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limits.Check(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func Test_UnmarshalWithLimits(t *testing.T) {
+	data, err := Marshal(map[string][]int{"a": {1, 2, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var value map[string][]int
+	if err = UnmarshalWithLimits(data, &value, Limits{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(value["a"]) != 3 {
+		t.Fatalf("unexpected value %v", value)
+	}
+
+	if err = UnmarshalWithLimits(data, &value, Limits{MaxNestedLevels: 1}); !errors.Is(err, ErrMaxNestedLevels) {
+		t.Fatalf("expected %v, got %v", ErrMaxNestedLevels, err)
+	}
+}
+
+func Test_UnmarshalWithLimits_DecodeModes(t *testing.T) {
+	data, err := Marshal([]any{[]any{[]any{[]any{[]any{1}}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 100; i++ {
+		var value any
+		if err = UnmarshalWithLimits(data, &value, Limits{MaxNestedLevels: 5, MaxArrayElements: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	decodeModes := 0
+	limitsDecodeModes.Range(func(key, value any) bool {
+		decodeModes++
+		return true
+	})
+	if decodeModes > 4 {
+		t.Fatalf("expected at most 4 decode modes, got %d", decodeModes)
+	}
+}
@@ -0,0 +1,51 @@
+package cbor
+
+import (
+	"github.com/fxamacker/cbor/v2"
+)
+
+var (
+	encodeMode cbor.EncMode
+	decodeMode cbor.DecMode
+)
+
+func init() {
+	var err error
+
+	encodeMode, err = EncOptions().EncMode()
+	if err != nil {
+		panic(err)
+	}
+
+	decodeMode, err = DecOptions().DecMode()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// EncOptions are the options used to encode every type in the module: core deterministic
+// encoding, with time.Time values encoded as tag 0 tdate strings.
+func EncOptions() cbor.EncOptions {
+	encOptions := cbor.CoreDetEncOptions()
+	encOptions.Time = cbor.TimeRFC3339
+	encOptions.TimeTag = cbor.EncTagRequired
+	return encOptions
+}
+
+// DecOptions are the options used to decode every type in the module, rejecting
+// duplicate map keys.
+func DecOptions() cbor.DecOptions {
+	return cbor.DecOptions{
+		DupMapKey: cbor.DupMapKeyEnforcedAPF,
+	}
+}
+
+// Marshal encodes value using EncOptions.
+func Marshal(value any) ([]byte, error) {
+	return encodeMode.Marshal(value)
+}
+
+// Unmarshal decodes data into value using DecOptions.
+func Unmarshal(data []byte, value any) error {
+	return decodeMode.Unmarshal(data, value)
+}
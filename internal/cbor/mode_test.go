@@ -0,0 +1,56 @@
+package cbor
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func Test_Marshal_Deterministic(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{
+			name:  "map keys sorted",
+			value: map[any]any{"bb": 1, "a": 2, 10: 3, -1: 4},
+			want:  "a40a0320046161026262620" + "1",
+		},
+		{
+			name:  "shortest float",
+			value: 1.5,
+			want:  "f93e00",
+		},
+		{
+			name:  "tdate",
+			value: time.Date(2020, time.October, 1, 13, 30, 2, 0, time.UTC),
+			want:  "c074323032302d31302d30315431333a33303a30325a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Marshal(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Fatalf("expected %s, got %x", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_Unmarshal_DuplicateMapKey(t *testing.T) {
+	// {1: 1, 1: 2}
+	data, err := hex.DecodeString("a201010102")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var value map[int]int
+	if err = Unmarshal(data, &value); err == nil {
+		t.Fatal("expected error")
+	}
+}
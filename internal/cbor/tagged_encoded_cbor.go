@@ -16,7 +16,7 @@ const (
 )
 
 const (
-	tagEncodedCBOR = 24
+	TagEncodedCBOR = 24
 )
 
 var (
@@ -34,18 +34,18 @@ func init() {
 	err := ts.Add(
 		cbor.TagOptions{DecTag: cbor.DecTagRequired, EncTag: cbor.EncTagRequired},
 		reflect.TypeOf(bstr(nil)),
-		tagEncodedCBOR,
+		TagEncodedCBOR,
 	)
 	if err != nil {
 		panic(err)
 	}
 
-	encodeModeTaggedEncodedCBOR, err = cbor.EncOptions{}.EncModeWithTags(ts)
+	encodeModeTaggedEncodedCBOR, err = EncOptions().EncModeWithTags(ts)
 	if err != nil {
 		panic(err)
 	}
 
-	decodeModeTaggedEncodedCBOR, err = cbor.DecOptions{}.DecModeWithTags(ts)
+	decodeModeTaggedEncodedCBOR, err = DecOptions().DecModeWithTags(ts)
 	if err != nil {
 		panic(err)
 	}
@@ -59,7 +59,7 @@ type TaggedEncodedCBOR struct {
 }
 
 func MarshalToNewTaggedEncodedCBOR(value any) (*TaggedEncodedCBOR, error) {
-	untaggedValue, err := cbor.Marshal(value)
+	untaggedValue, err := Marshal(value)
 	if err != nil {
 		return nil, err
 	}
@@ -37,7 +37,7 @@ func Test_MarshalToTaggedEncodedCBOR(t *testing.T) {
 			value: "string",
 			want: &TaggedEncodedCBOR{
 				TaggedValue: []byte{
-					cborMajorTypeTaggedValue | cborArgumentLength1, tagEncodedCBOR,
+					cborMajorTypeTaggedValue | cborArgumentLength1, TagEncodedCBOR,
 					cborMajorTypeBstr | 7,
 					cborMajorTypeStr | 6, 's', 't', 'r', 'i', 'n', 'g',
 				},
@@ -51,7 +51,7 @@ func Test_MarshalToTaggedEncodedCBOR(t *testing.T) {
 			value: nil,
 			want: &TaggedEncodedCBOR{
 				TaggedValue: []byte{
-					cborMajorTypeTaggedValue | cborArgumentLength1, tagEncodedCBOR,
+					cborMajorTypeTaggedValue | cborArgumentLength1, TagEncodedCBOR,
 					cborMajorTypeBstr | 1,
 					Null,
 				},
@@ -87,7 +87,7 @@ func Test_NewTaggedEncodedCBOR(t *testing.T) {
 			untaggedValue: []byte{1, 2, 3, 4},
 			want: &TaggedEncodedCBOR{
 				TaggedValue: []byte{
-					cborMajorTypeTaggedValue | cborArgumentLength1, tagEncodedCBOR,
+					cborMajorTypeTaggedValue | cborArgumentLength1, TagEncodedCBOR,
 					cborMajorTypeBstr | 4, 1, 2, 3, 4,
 				},
 				UntaggedValue: []byte{1, 2, 3, 4},
@@ -103,7 +103,7 @@ func Test_NewTaggedEncodedCBOR(t *testing.T) {
 			untaggedValue: []byte{},
 			want: &TaggedEncodedCBOR{
 				TaggedValue: []byte{
-					cborMajorTypeTaggedValue | cborArgumentLength1, tagEncodedCBOR,
+					cborMajorTypeTaggedValue | cborArgumentLength1, TagEncodedCBOR,
 					cborMajorTypeBstr | 0,
 				},
 				UntaggedValue: []byte{},
@@ -135,7 +135,7 @@ func Test_TaggedEncodedCBOR_MarshalCBOR(t *testing.T) {
 			name: "marshal complete",
 			taggedEncodedCBOR: TaggedEncodedCBOR{
 				TaggedValue: []byte{
-					cborMajorTypeTaggedValue | cborArgumentLength1, tagEncodedCBOR,
+					cborMajorTypeTaggedValue | cborArgumentLength1, TagEncodedCBOR,
 					cborMajorTypeBstr | 1, cborEmptyMap,
 				},
 				UntaggedValue: []byte{
@@ -143,7 +143,7 @@ func Test_TaggedEncodedCBOR_MarshalCBOR(t *testing.T) {
 				},
 			},
 			want: []byte{
-				cborMajorTypeTaggedValue | cborArgumentLength1, tagEncodedCBOR,
+				cborMajorTypeTaggedValue | cborArgumentLength1, TagEncodedCBOR,
 				cborMajorTypeBstr | 1, cborEmptyMap,
 			},
 		},
@@ -151,12 +151,12 @@ func Test_TaggedEncodedCBOR_MarshalCBOR(t *testing.T) {
 			name: "marshal tagged only",
 			taggedEncodedCBOR: TaggedEncodedCBOR{
 				TaggedValue: []byte{
-					cborMajorTypeTaggedValue | cborArgumentLength1, tagEncodedCBOR,
+					cborMajorTypeTaggedValue | cborArgumentLength1, TagEncodedCBOR,
 					cborMajorTypeBstr | 1, cborEmptyMap,
 				},
 			},
 			want: []byte{
-				cborMajorTypeTaggedValue | cborArgumentLength1, tagEncodedCBOR,
+				cborMajorTypeTaggedValue | cborArgumentLength1, TagEncodedCBOR,
 				cborMajorTypeBstr | 1, cborEmptyMap,
 			},
 		},
@@ -204,12 +204,12 @@ func Test_TaggedEncodedCBOR_UnmarshalCBOR(t *testing.T) {
 		{
 			name: "unmarshal tagged",
 			data: []byte{
-				cborMajorTypeTaggedValue | cborArgumentLength1, tagEncodedCBOR,
+				cborMajorTypeTaggedValue | cborArgumentLength1, TagEncodedCBOR,
 				cborMajorTypeBstr | 1, cborEmptyMap,
 			},
 			want: TaggedEncodedCBOR{
 				TaggedValue: []byte{
-					cborMajorTypeTaggedValue | cborArgumentLength1, tagEncodedCBOR,
+					cborMajorTypeTaggedValue | cborArgumentLength1, TagEncodedCBOR,
 					cborMajorTypeBstr | 1, cborEmptyMap,
 				},
 				UntaggedValue: []byte{
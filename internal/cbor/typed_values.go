@@ -11,6 +11,20 @@ import (
 var (
 	ErrUnsupportedValue = errors.New("mdoc: cbor: unsupported value")
 	ErrUnknownType      = errors.New("mdoc: cbor: unknown type")
+	ErrUnexpectedTag    = errors.New("mdoc: cbor: unexpected tag")
+	ErrInvalidDate      = errors.New("mdoc: cbor: invalid date")
+)
+
+const (
+	TagTdate          = 0
+	TagFullDateDays   = 100
+	TagFullDateString = 1004
+)
+
+const (
+	// TdateLayout is RFC 3339 without fractional seconds, as required for tdate.
+	TdateLayout    = "2006-01-02T15:04:05Z"
+	FullDateLayout = time.DateOnly
 )
 
 type CBORType string
@@ -29,14 +43,14 @@ func MarshalTypedValue(cborType CBORType, value any) ([]byte, error) {
 	case CBORTypeTstr:
 		_, ok := value.(string)
 		if ok {
-			return cbor.Marshal(value)
+			return Marshal(value)
 		}
 		return nil, ErrUnsupportedValue
 
 	case CBORTypeBstr:
 		_, ok := value.([]byte)
 		if ok {
-			return cbor.Marshal(value)
+			return Marshal(value)
 		}
 		return nil, ErrUnsupportedValue
 
@@ -59,33 +73,115 @@ func MarshalTypedValue(cborType CBORType, value any) ([]byte, error) {
 			supported = v.Sign() >= 0
 		}
 		if supported {
-			return cbor.Marshal(value)
+			return Marshal(value)
 		}
 		return nil, ErrUnsupportedValue
 
 	case CBORTypeBool:
 		_, ok := value.(bool)
 		if ok {
-			return cbor.Marshal(value)
+			return Marshal(value)
 		}
 		return nil, ErrUnsupportedValue
 
 	case CBORTypeTdate:
-		switch datetime := value.(type) {
-		case time.Time, *time.Time:
-			// TODO configure marshaller
-			return cbor.Marshal(datetime)
+		datetime, ok := timeValue(value)
+		if ok {
+			return MarshalTdate(datetime)
 		}
 		return nil, ErrUnsupportedValue
 
 	case CBORTypeFullDate:
-		switch datetime := value.(type) {
-		case time.Time, *time.Time:
-			// TODO configure marshaller
-			return cbor.Marshal(datetime)
+		datetime, ok := timeValue(value)
+		if ok {
+			return MarshalFullDate(datetime)
 		}
 		return nil, ErrUnsupportedValue
 	}
 
 	return nil, ErrUnknownType
 }
+
+func timeValue(value any) (time.Time, bool) {
+	switch datetime := value.(type) {
+	case time.Time:
+		return datetime, true
+	case *time.Time:
+		if datetime != nil {
+			return *datetime, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// MarshalTdate encodes datetime as a tag 0 date/time string in UTC, without fractional
+// seconds.
+func MarshalTdate(datetime time.Time) ([]byte, error) {
+	return Marshal(cbor.Tag{
+		Number:  TagTdate,
+		Content: datetime.UTC().Format(TdateLayout),
+	})
+}
+
+// MarshalFullDate encodes the date of datetime, in its own location, as a tag 1004
+// full-date string.
+func MarshalFullDate(datetime time.Time) ([]byte, error) {
+	return Marshal(cbor.Tag{
+		Number:  TagFullDateString,
+		Content: datetime.Format(FullDateLayout),
+	})
+}
+
+// UnmarshalTdate decodes a tag 0 date/time string.
+func UnmarshalTdate(data []byte) (time.Time, error) {
+	var tag cbor.RawTag
+	if err := Unmarshal(data, &tag); err != nil {
+		return time.Time{}, err
+	}
+
+	if tag.Number != TagTdate {
+		return time.Time{}, ErrUnexpectedTag
+	}
+
+	var s string
+	if err := Unmarshal(tag.Content, &s); err != nil {
+		return time.Time{}, err
+	}
+
+	datetime, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+	return datetime, nil
+}
+
+// UnmarshalFullDate decodes a tag 1004 full-date string or a tag 100 count of days since
+// 1970-01-01, returning midnight UTC at the start of the date.
+func UnmarshalFullDate(data []byte) (time.Time, error) {
+	var tag cbor.RawTag
+	if err := Unmarshal(data, &tag); err != nil {
+		return time.Time{}, err
+	}
+
+	switch tag.Number {
+	case TagFullDateString:
+		var s string
+		if err := Unmarshal(tag.Content, &s); err != nil {
+			return time.Time{}, err
+		}
+		date, err := time.Parse(FullDateLayout, s)
+		if err != nil {
+			return time.Time{}, ErrInvalidDate
+		}
+		return date, nil
+
+	case TagFullDateDays:
+		var days int64
+		if err := Unmarshal(tag.Content, &days); err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(days*24*60*60, 0).UTC(), nil
+	}
+
+	return time.Time{}, ErrUnexpectedTag
+}
@@ -0,0 +1,172 @@
+package cbor
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_MarshalTypedValue_Dates(t *testing.T) {
+	auckland := time.FixedZone("NZDT", 13*60*60)
+
+	tests := []struct {
+		name     string
+		cborType CBORType
+		value    any
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "tdate",
+			cborType: CBORTypeTdate,
+			value:    time.Date(2020, time.October, 1, 13, 30, 2, 0, time.UTC),
+			want:     "c074323032302d31302d30315431333a33303a30325a", // 0("2020-10-01T13:30:02Z")
+		},
+		{
+			name:     "tdate fractional seconds and offset",
+			cborType: CBORTypeTdate,
+			value:    time.Date(2020, time.October, 2, 2, 30, 2, 123456789, auckland),
+			want:     "c074323032302d31302d30315431333a33303a30325a",
+		},
+		{
+			name:     "tdate pointer",
+			cborType: CBORTypeTdate,
+			value:    func() *time.Time { t := time.Date(2020, time.October, 1, 13, 30, 2, 0, time.UTC); return &t }(),
+			want:     "c074323032302d31302d30315431333a33303a30325a",
+		},
+		{
+			name:     "full-date",
+			cborType: CBORTypeFullDate,
+			value:    time.Date(1971, time.September, 1, 0, 0, 0, 0, time.UTC),
+			want:     "d903ec6a313937312d30392d3031", // 1004("1971-09-01")
+		},
+		{
+			name:     "full-date local date",
+			cborType: CBORTypeFullDate,
+			value:    time.Date(1971, time.September, 1, 8, 0, 0, 0, auckland),
+			want:     "d903ec6a313937312d30392d3031",
+		},
+		{
+			name:     "tdate string",
+			cborType: CBORTypeTdate,
+			value:    "2020-10-01T13:30:02Z",
+			wantErr:  ErrUnsupportedValue,
+		},
+		{
+			name:     "full-date nil",
+			cborType: CBORTypeFullDate,
+			value:    (*time.Time)(nil),
+			wantErr:  ErrUnsupportedValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MarshalTypedValue(tt.cborType, tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Fatalf("expected %s, got %x", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_UnmarshalTdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    time.Time
+		wantErr error
+	}{
+		{
+			name: "UTC",
+			data: "c074323032302d31302d30315431333a33303a30325a",
+			want: time.Date(2020, time.October, 1, 13, 30, 2, 0, time.UTC),
+		},
+		{
+			name:    "full-date",
+			data:    "d903ec6a313937312d30392d3031",
+			wantErr: ErrUnexpectedTag,
+		},
+		{
+			name:    "invalid",
+			data:    "c06a313937312d30392d3031", // 0("1971-09-01")
+			wantErr: ErrInvalidDate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := UnmarshalTdate(data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_UnmarshalFullDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    time.Time
+		wantErr error
+	}{
+		{
+			name: "string",
+			data: "d903ec6a313937312d30392d3031",
+			want: time.Date(1971, time.September, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "days",
+			data: "d864190269", // 100(617)
+			want: time.Date(1971, time.September, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "days before epoch",
+			data: "d86420", // 100(-1)
+			want: time.Date(1969, time.December, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "tdate",
+			data:    "c074323032302d31302d30315431333a33303a30325a",
+			wantErr: ErrUnexpectedTag,
+		},
+		{
+			name:    "invalid",
+			data:    "d903ec6a313937312d31332d3031", // 1004("1971-13-01")
+			wantErr: ErrInvalidDate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := UnmarshalFullDate(data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package cose
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+
+	mdoccbor "github.com/alex-richards/go-mdoc/internal/cbor"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/veraison/go-cose"
+)
+
+var (
+	ErrMissingPayload       = errors.New("mdoc: cose: missing payload")
+	ErrMissingTag           = errors.New("mdoc: cose: missing tag")
+	ErrUnsupportedAlgorithm = errors.New("mdoc: cose: unsupported algorithm")
+	ErrInvalidTag           = errors.New("mdoc: cose: invalid tag")
+)
+
+const (
+	AlgorithmHMAC256 cose.Algorithm = 5
+)
+
+const (
+	mac0Context = "MAC0"
+)
+
+// Mac0Message is an untagged COSE_Mac0 message, as go-cose has no MAC support.
+type Mac0Message struct {
+	Headers cose.Headers
+	Payload []byte
+	Tag     []byte
+}
+
+type mac0Message struct {
+	_           struct{} `cbor:",toarray"`
+	Protected   cbor.RawMessage
+	Unprotected cbor.RawMessage
+	Payload     []byte
+	Tag         []byte
+}
+
+func (m *Mac0Message) MarshalCBOR() ([]byte, error) {
+	if len(m.Tag) == 0 {
+		return nil, ErrMissingTag
+	}
+
+	protected, err := m.Headers.MarshalProtected()
+	if err != nil {
+		return nil, err
+	}
+
+	unprotected, err := m.Headers.MarshalUnprotected()
+	if err != nil {
+		return nil, err
+	}
+
+	return mdoccbor.Marshal(&mac0Message{
+		Protected:   protected,
+		Unprotected: unprotected,
+		Payload:     m.Payload,
+		Tag:         m.Tag,
+	})
+}
+
+func (m *Mac0Message) UnmarshalCBOR(data []byte) error {
+	var raw mac0Message
+	if err := mdoccbor.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if len(raw.Tag) == 0 {
+		return ErrMissingTag
+	}
+
+	headers := cose.Headers{
+		RawProtected:   raw.Protected,
+		RawUnprotected: raw.Unprotected,
+	}
+	if err := headers.UnmarshalFromRaw(); err != nil {
+		return err
+	}
+
+	m.Headers = headers
+	m.Payload = raw.Payload
+	m.Tag = raw.Tag
+
+	return nil
+}
+
+// CreateTag calculates the HMAC 256/256 tag over the message and stores it in m.Tag.
+func (m *Mac0Message) CreateTag(external []byte, key []byte) error {
+	if m.Headers.Protected == nil {
+		m.Headers.Protected = cose.ProtectedHeader{}
+	}
+
+	algorithm, err := m.Headers.Protected.Algorithm()
+	switch {
+	case errors.Is(err, cose.ErrAlgorithmNotFound):
+		m.Headers.Protected.SetAlgorithm(AlgorithmHMAC256)
+	case err != nil:
+		return err
+	case algorithm != AlgorithmHMAC256:
+		return ErrUnsupportedAlgorithm
+	}
+
+	tag, err := m.tag(external, key)
+	if err != nil {
+		return err
+	}
+
+	m.Tag = tag
+	return nil
+}
+
+// VerifyTag checks m.Tag against the HMAC 256/256 tag calculated over the message.
+func (m *Mac0Message) VerifyTag(external []byte, key []byte) error {
+	if len(m.Tag) == 0 {
+		return ErrMissingTag
+	}
+
+	algorithm, err := m.Headers.Protected.Algorithm()
+	if err != nil {
+		return err
+	}
+	if algorithm != AlgorithmHMAC256 {
+		return ErrUnsupportedAlgorithm
+	}
+
+	tag, err := m.tag(external, key)
+	if err != nil {
+		return err
+	}
+
+	if !hmac.Equal(tag, m.Tag) {
+		return ErrInvalidTag
+	}
+
+	return nil
+}
+
+func (m *Mac0Message) tag(external []byte, key []byte) ([]byte, error) {
+	if m.Payload == nil {
+		return nil, ErrMissingPayload
+	}
+
+	protected, err := m.Headers.MarshalProtected()
+	if err != nil {
+		return nil, err
+	}
+
+	if external == nil {
+		external = []byte{}
+	}
+
+	toBeMACed, err := mdoccbor.Marshal([]any{
+		mac0Context,
+		cbor.RawMessage(protected),
+		external,
+		m.Payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(toBeMACed)
+	return mac.Sum(nil), nil
+}
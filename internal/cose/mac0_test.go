@@ -0,0 +1,57 @@
+package cose
+
+import (
+	"testing"
+
+	"github.com/alex-richards/go-mdoc/internal/testutil"
+	"github.com/fxamacker/cbor/v2"
+)
+
+func Test_Mac0Message_CBOR_RoundTrip(t *testing.T) {
+	mac0Bytes := testutil.DecodeHex(t, "8443a10105a0f65820e99521a85ad7891b806a07f8b5388a332d92c189a7bf293ee1f543405ae6824d")
+
+	var mac0 Mac0Message
+	if err := cbor.Unmarshal(mac0Bytes, &mac0); err != nil {
+		t.Fatal(err)
+	}
+
+	algorithm, err := mac0.Headers.Protected.Algorithm()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if algorithm != AlgorithmHMAC256 {
+		t.Fatalf("algorithm = %v", algorithm)
+	}
+	if mac0.Payload != nil {
+		t.Fatal("expected detached payload")
+	}
+
+	mac0BytesAgain, err := cbor.Marshal(&mac0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutil.ExpectCBOR(t, mac0Bytes, mac0BytesAgain)
+}
+
+func Test_Mac0Message_Tag(t *testing.T) {
+	key := []byte{1, 2, 3, 4}
+
+	mac0 := Mac0Message{Payload: []byte{5, 6, 7, 8}}
+	if err := mac0.CreateTag(nil, key); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mac0.VerifyTag(nil, key); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mac0.VerifyTag(nil, []byte{4, 3, 2, 1}); err != ErrInvalidTag {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidTag)
+	}
+
+	mac0.Payload = []byte{8, 7, 6, 5}
+	if err := mac0.VerifyTag(nil, key); err != ErrInvalidTag {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidTag)
+	}
+}
@@ -0,0 +1,203 @@
+package spec
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/alex-richards/go-mdoc"
+	mdocecdsa "github.com/alex-richards/go-mdoc/cipher_suite/ecdsa"
+	"github.com/alex-richards/go-mdoc/issuer"
+	"github.com/alex-richards/go-mdoc/reader"
+)
+
+func newIACA(t testing.TB, rand io.Reader) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	iacaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iacaCertificateDER, err := issuer.NewIACACertificate(
+		rand,
+		iacaKey, iacaKey.Public(),
+		*big.NewInt(1234),
+		"Test IACA",
+		"NZ", nil,
+		time.UnixMilli(1000),
+		time.UnixMilli(2000),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iacaCertificate, err := x509.ParseCertificate(iacaCertificateDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return iacaCertificate, iacaKey
+}
+
+func newIssuerAuthority(t testing.TB, rand io.Reader, iacaCertificate *x509.Certificate, iacaKey *ecdsa.PrivateKey) *issuer.IssuerAuthority {
+	t.Helper()
+
+	documentSignerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documentSigner, err := mdocecdsa.NewPrivateKey(documentSignerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documentSignerCertificateDER, err := issuer.NewDocumentSignerCertificate(
+		rand,
+		iacaKey, iacaCertificate,
+		documentSignerKey.Public(),
+		*big.NewInt(5678),
+		"Test Document Signer",
+		nil,
+		time.UnixMilli(1000),
+		time.UnixMilli(2000),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documentSignerCertificate, err := x509.ParseCertificate(documentSignerCertificateDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &issuer.IssuerAuthority{
+		Signer:                    documentSigner.Signer,
+		DocumentSignerCertificate: documentSignerCertificate,
+	}
+}
+
+func newIssuerSigned(
+	t testing.TB,
+	rand io.Reader,
+	issuerAuthority *issuer.IssuerAuthority,
+	docType mdoc.DocType,
+	items map[mdoc.NameSpace]map[mdoc.DataElementIdentifier]mdoc.DataElementValue,
+	sDeviceKey *mdoc.PublicKey,
+) *mdoc.IssuerSigned {
+	t.Helper()
+
+	nameSpaces := make(mdoc.IssuerNameSpaces, len(items))
+	for nameSpace, dataElements := range items {
+		digestID := mdoc.DigestID(0)
+		for dataElementIdentifier, dataElementValue := range dataElements {
+			issuerSignedItemBytes, err := mdoc.NewIssuerSignedItemBytes(rand, digestID, dataElementIdentifier, dataElementValue)
+			if err != nil {
+				t.Fatal(err)
+			}
+			nameSpaces[nameSpace] = append(nameSpaces[nameSpace], *issuerSignedItemBytes)
+			digestID++
+		}
+	}
+
+	mobileSecurityObject, err := issuer.NewMobileSecurityObject(
+		docType,
+		mdoc.DigestAlgorithmSHA256,
+		nameSpaces,
+		sDeviceKey,
+		&mdoc.ValidityInfo{
+			Signed:     time.UnixMilli(1000),
+			ValidFrom:  time.UnixMilli(1000),
+			ValidUntil: time.UnixMilli(2000),
+		},
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuerAuth, err := issuer.NewIssuerAuth(rand, *issuerAuthority, mobileSecurityObject)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &mdoc.IssuerSigned{
+		NameSpaces: nameSpaces,
+		IssuerAuth: *issuerAuth,
+	}
+}
+
+func newReaderAuthority(t testing.TB, rand io.Reader) (*reader.ReaderAuthority, *x509.Certificate) {
+	t.Helper()
+
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Reader Root"},
+		NotBefore:             time.UnixMilli(1000),
+		NotAfter:              time.UnixMilli(2000),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	rootCertificateDER, err := x509.CreateCertificate(rand, rootTemplate, rootTemplate, rootKey.Public(), rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootCertificate, err := x509.ParseCertificate(rootCertificateDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readerAuthCertificateDER, err := x509.CreateCertificate(
+		rand,
+		&x509.Certificate{
+			SerialNumber:       big.NewInt(2),
+			Subject:            pkix.Name{CommonName: "Test Reader"},
+			NotBefore:          time.UnixMilli(1000),
+			NotAfter:           time.UnixMilli(2000),
+			KeyUsage:           x509.KeyUsageDigitalSignature,
+			UnknownExtKeyUsage: []asn1.ObjectIdentifier{{1, 0, 18013, 5, 1, 6}},
+		},
+		rootCertificate,
+		readerKey.Public(),
+		rootKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readerAuthCertificate, err := x509.ParseCertificate(readerAuthCertificateDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readerSigner, err := mdocecdsa.NewPrivateKey(readerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &reader.ReaderAuthority{
+		Signer:          readerSigner.Signer,
+		RootCertificate: readerAuthCertificate,
+	}, rootCertificate
+}
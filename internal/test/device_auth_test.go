@@ -1,13 +1,16 @@
 package spec
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/alex-richards/go-mdoc"
-	"github.com/alex-richards/go-mdoc/cipher_suite/ecdsa"
+	"github.com/alex-richards/go-mdoc/cipher_suite"
 	"github.com/alex-richards/go-mdoc/holder"
 	"github.com/alex-richards/go-mdoc/internal/cbor"
+	"github.com/alex-richards/go-mdoc/internal/cose"
 	"github.com/alex-richards/go-mdoc/internal/testutil"
+	gocose "github.com/veraison/go-cose"
 )
 
 func Test_DeviceAuth_Verify(t *testing.T) {
@@ -16,6 +19,7 @@ func Test_DeviceAuth_Verify(t *testing.T) {
 	tests := []struct {
 		name                            string
 		curve                           mdoc.Curve
+		sign                            bool
 		deviceAuthenticationBytesCreate *cbor.TaggedEncodedCBOR
 		deviceAuthenticationBytesVerify *cbor.TaggedEncodedCBOR
 		wantErr                         error
@@ -23,6 +27,7 @@ func Test_DeviceAuth_Verify(t *testing.T) {
 		{
 			name:  "Sign P256",
 			curve: mdoc.CurveP256,
+			sign:  true,
 			deviceAuthenticationBytesCreate: &cbor.TaggedEncodedCBOR{
 				TaggedValue: []byte{1, 2, 3, 4},
 			},
@@ -30,24 +35,77 @@ func Test_DeviceAuth_Verify(t *testing.T) {
 		{
 			name:  "Sign P521",
 			curve: mdoc.CurveP521,
+			sign:  true,
 			deviceAuthenticationBytesCreate: &cbor.TaggedEncodedCBOR{
 				TaggedValue: []byte{1, 2, 3, 4},
 			},
 		},
+		{
+			name:  "Sign P256 mismatched",
+			curve: mdoc.CurveP256,
+			sign:  true,
+			deviceAuthenticationBytesCreate: &cbor.TaggedEncodedCBOR{
+				TaggedValue: []byte{1, 2, 3, 4},
+			},
+			deviceAuthenticationBytesVerify: &cbor.TaggedEncodedCBOR{
+				TaggedValue: []byte{5, 6, 7, 8},
+			},
+			wantErr: gocose.ErrVerification,
+		},
+		{
+			name:  "MAC P256",
+			curve: mdoc.CurveP256,
+			deviceAuthenticationBytesCreate: &cbor.TaggedEncodedCBOR{
+				TaggedValue: []byte{1, 2, 3, 4},
+			},
+		},
+		{
+			name:  "MAC X25519",
+			curve: mdoc.CurveX25519,
+			deviceAuthenticationBytesCreate: &cbor.TaggedEncodedCBOR{
+				TaggedValue: []byte{1, 2, 3, 4},
+			},
+		},
+		{
+			name:  "MAC P256 mismatched",
+			curve: mdoc.CurveP256,
+			deviceAuthenticationBytesCreate: &cbor.TaggedEncodedCBOR{
+				TaggedValue: []byte{1, 2, 3, 4},
+			},
+			deviceAuthenticationBytesVerify: &cbor.TaggedEncodedCBOR{
+				TaggedValue: []byte{5, 6, 7, 8},
+			},
+			wantErr: cose.ErrInvalidTag,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			sDeviceKey, err := ecdsa.GeneratePrivateKey(rand, tt.curve)
+			sDeviceKey, err := cipher_suite.GeneratePrivateKey(rand, tt.curve, tt.sign)
+			if err != nil {
+				t.Fatal(err)
+			}
+			eReaderKey, err := cipher_suite.GeneratePrivateKey(rand, tt.curve, false)
 			if err != nil {
 				t.Fatal(err)
 			}
 
-			deviceAuth, err := holder.NewDeviceAuth(rand, sDeviceKey, tt.deviceAuthenticationBytesCreate)
+			sessionTranscript := newSessionTranscript(t, &eReaderKey.PublicKey)
+
+			sessionTranscriptBytes, err := mdoc.NewSessionTranscriptBytes(sessionTranscript)
 			if err != nil {
 				t.Fatal(err)
 			}
 
+			deviceAuth, err := holder.NewDeviceAuth(rand, sDeviceKey, sessionTranscript, tt.deviceAuthenticationBytesCreate)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.sign != (deviceAuth.DeviceSignature != nil) || tt.sign == (deviceAuth.DeviceMAC != nil) {
+				t.Fatal("unexpected device auth type")
+			}
+
 			var deviceAuthenticationBytesVerify *cbor.TaggedEncodedCBOR
 			if tt.deviceAuthenticationBytesVerify != nil {
 				deviceAuthenticationBytesVerify = tt.deviceAuthenticationBytesVerify
@@ -55,16 +113,36 @@ func Test_DeviceAuth_Verify(t *testing.T) {
 				deviceAuthenticationBytesVerify = tt.deviceAuthenticationBytesCreate
 			}
 
-			err = deviceAuth.Verify(&sDeviceKey.PublicKey, deviceAuthenticationBytesVerify)
+			err = deviceAuth.Verify(&sDeviceKey.PublicKey, eReaderKey, sessionTranscriptBytes, deviceAuthenticationBytesVerify)
 
 			switch {
 			case tt.wantErr == nil && err != nil:
 				t.Fatal(err)
 			case tt.wantErr != nil && err == nil:
 				t.Fatal()
-			case tt.wantErr != err:
-				t.Fatal()
+			case !errors.Is(err, tt.wantErr):
+				t.Fatal(err)
 			}
 		})
 	}
 }
+
+func newSessionTranscript(t testing.TB, eReaderKey *mdoc.PublicKey) *mdoc.SessionTranscript {
+	t.Helper()
+
+	deviceEngagementBytes, err := cbor.NewTaggedEncodedCBOR([]byte{1, 2, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eReaderKeyBytes, err := cbor.MarshalToNewTaggedEncodedCBOR(eReaderKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &mdoc.SessionTranscript{
+		DeviceEngagementBytes: deviceEngagementBytes,
+		EReaderKeyBytes:       eReaderKeyBytes,
+		Handover:              mdoc.QRHandover{},
+	}
+}
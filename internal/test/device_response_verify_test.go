@@ -0,0 +1,188 @@
+package spec
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/alex-richards/go-mdoc/cipher_suite"
+	"github.com/alex-richards/go-mdoc/holder"
+	"github.com/alex-richards/go-mdoc/internal/testutil"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_DeviceResponse_Verify(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	iacaCertificate, iacaKey := newIACA(t, rand)
+	issuerAuthority := newIssuerAuthority(t, rand, iacaCertificate, iacaKey)
+	rootCertificates := []*x509.Certificate{iacaCertificate}
+
+	sDeviceKey, err := cipher_suite.GeneratePrivateKey(rand, mdoc.CurveP256, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eReaderKey, err := cipher_suite.GeneratePrivateKey(rand, mdoc.CurveP256, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessionTranscript := newSessionTranscript(t, &eReaderKey.PublicKey)
+
+	issuerSigned := newIssuerSigned(
+		t, rand, issuerAuthority,
+		"docType1",
+		map[mdoc.NameSpace]map[mdoc.DataElementIdentifier]mdoc.DataElementValue{
+			"nameSpace1": {
+				"dataElementIdentifier1": "value1",
+				"dataElementIdentifier2": "value2",
+			},
+		},
+		&sDeviceKey.PublicKey,
+	)
+
+	newDeviceRequest := func(t *testing.T, itemsRequests ...*mdoc.ItemsRequest) *mdoc.DeviceRequest {
+		docRequests := make([]mdoc.DocRequest, len(itemsRequests))
+		for i, itemsRequest := range itemsRequests {
+			docRequest, err := mdoc.NewDocRequest(itemsRequest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			docRequests[i] = *docRequest
+		}
+		return mdoc.NewDeviceRequest(docRequests)
+	}
+
+	requestOne := &mdoc.ItemsRequest{
+		DocType:    "docType1",
+		NameSpaces: mdoc.NameSpaces{"nameSpace1": {"dataElementIdentifier1": false}},
+	}
+	requestBoth := &mdoc.ItemsRequest{
+		DocType: "docType1",
+		NameSpaces: mdoc.NameSpaces{"nameSpace1": {
+			"dataElementIdentifier1": false,
+			"dataElementIdentifier2": false,
+		}},
+	}
+	requestOther := &mdoc.ItemsRequest{
+		DocType:    "docType2",
+		NameSpaces: mdoc.NameSpaces{"nameSpace1": {"dataElementIdentifier1": false}},
+	}
+
+	tests := []struct {
+		name       string
+		requested  *mdoc.ItemsRequest
+		verified   *mdoc.ItemsRequest
+		nilPolicy  bool
+		nilRequest bool
+		modify     func(deviceResponse *mdoc.DeviceResponse)
+		wantClaims mdoc.Claims
+		wantErr    error
+	}{
+		{
+			name:      "Valid",
+			requested: requestBoth,
+			verified:  requestBoth,
+			wantClaims: mdoc.Claims{"nameSpace1": {
+				"dataElementIdentifier1": "value1",
+				"dataElementIdentifier2": "value2",
+			}},
+		},
+		{
+			name:      "NilPolicy",
+			requested: requestBoth,
+			verified:  requestBoth,
+			nilPolicy: true,
+			wantClaims: mdoc.Claims{"nameSpace1": {
+				"dataElementIdentifier1": "value1",
+				"dataElementIdentifier2": "value2",
+			}},
+		},
+		{
+			name:       "NilRequest",
+			requested:  requestBoth,
+			nilRequest: true,
+			wantErr:    mdoc.ErrMissingDeviceRequest,
+		},
+		{
+			name:      "UnrequestedDataElement",
+			requested: requestBoth,
+			verified:  requestOne,
+			wantErr:   mdoc.ErrUnrequestedDataElement,
+		},
+		{
+			name:      "UnrequestedDocType",
+			requested: requestBoth,
+			verified:  requestOther,
+			wantErr:   mdoc.ErrUnrequestedDocType,
+		},
+		{
+			name:      "UnsupportedVersion",
+			requested: requestBoth,
+			verified:  requestBoth,
+			modify:    func(deviceResponse *mdoc.DeviceResponse) { deviceResponse.Version = "2.0" },
+			wantErr:   mdoc.ErrDeviceResponseUnsupportedVersion,
+		},
+		{
+			name:      "Status",
+			requested: requestBoth,
+			verified:  requestBoth,
+			modify:    func(deviceResponse *mdoc.DeviceResponse) { deviceResponse.Status = mdoc.StatusCodeGeneralError },
+			wantErr:   mdoc.ErrDeviceResponseStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deviceResponse, err := holder.NewDeviceResponse(
+				newDeviceRequest(t, tt.requested),
+				map[mdoc.DocType]mdoc.IssuerSigned{"docType1": *issuerSigned},
+				nil,
+				rand,
+				sDeviceKey,
+				sessionTranscript,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.modify != nil {
+				tt.modify(deviceResponse)
+			}
+
+			policy := mdoc.DefaultVerifierPolicy()
+			if tt.nilPolicy {
+				policy = nil
+			}
+
+			var deviceRequest *mdoc.DeviceRequest
+			if !tt.nilRequest {
+				deviceRequest = newDeviceRequest(t, tt.verified)
+			}
+
+			verifiedDeviceResponse, err := deviceResponse.Verify(
+				policy,
+				deviceRequest,
+				rootCertificates,
+				time.UnixMilli(1500),
+				eReaderKey,
+				sessionTranscript,
+			)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if len(verifiedDeviceResponse.Documents) != 1 {
+				t.Fatalf("expected 1 document, got %d", len(verifiedDeviceResponse.Documents))
+			}
+			if diff := cmp.Diff(tt.wantClaims, verifiedDeviceResponse.Documents[0].IssuerSigned); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,479 @@
+package spec
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/alex-richards/go-mdoc/cipher_suite"
+	"github.com/alex-richards/go-mdoc/holder"
+	"github.com/alex-richards/go-mdoc/internal/testutil"
+	"github.com/alex-richards/go-mdoc/reader"
+	"github.com/alex-richards/go-mdoc/session"
+)
+
+func Test_HolderSession(t *testing.T) {
+	tests := []struct {
+		name              string
+		consent           mdoc.NameSpaces
+		wantItems         []mdoc.DataElementIdentifier
+		wantDocumentError bool
+	}{
+		{
+			name: "ApproveAll",
+			consent: mdoc.NameSpaces{
+				"nameSpace1": {"dataElementIdentifier1": false, "dataElementIdentifier2": false},
+			},
+			wantItems: []mdoc.DataElementIdentifier{"dataElementIdentifier1", "dataElementIdentifier2"},
+		},
+		{
+			name: "ApproveSome",
+			consent: mdoc.NameSpaces{
+				"nameSpace1": {"dataElementIdentifier2": false},
+			},
+			wantItems: []mdoc.DataElementIdentifier{"dataElementIdentifier2"},
+		},
+		{
+			name:              "Deny",
+			consent:           mdoc.NameSpaces{},
+			wantDocumentError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rand := testutil.NewDeterministicRand(t)
+			now := time.UnixMilli(1500)
+
+			iacaCertificate, iacaKey := newIACA(t, rand)
+			issuerAuthority := newIssuerAuthority(t, rand, iacaCertificate, iacaKey)
+			readerAuthority, readerRootCertificate := newReaderAuthority(t, rand)
+
+			sDeviceKey, err := cipher_suite.GeneratePrivateKey(rand, mdoc.CurveP256, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			issuerSigned := newIssuerSigned(
+				t, rand, issuerAuthority,
+				"docType1",
+				map[mdoc.NameSpace]map[mdoc.DataElementIdentifier]mdoc.DataElementValue{
+					"nameSpace1": {
+						"dataElementIdentifier1": "value1",
+						"dataElementIdentifier2": "value2",
+					},
+				},
+				&sDeviceKey.PublicKey,
+			)
+
+			var consentReaderCertificate *x509.Certificate
+			holderSession, err := holder.NewSession(
+				rand,
+				mdoc.CurveP256,
+				nil,
+				mdoc.QRHandover{},
+				map[mdoc.DocType]mdoc.IssuerSigned{"docType1": *issuerSigned},
+				sDeviceKey,
+				[]*x509.Certificate{readerRootCertificate},
+				nil,
+				func(docType mdoc.DocType, nameSpaces mdoc.NameSpaces, readerCertificate *x509.Certificate) (mdoc.NameSpaces, error) {
+					consentReaderCertificate = readerCertificate
+					return tt.consent, nil
+				},
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			readerSession, err := reader.NewSession(
+				rand,
+				holderSession.DeviceEngagementBytes(),
+				mdoc.QRHandover{},
+				readerAuthority,
+				[]*x509.Certificate{iacaCertificate},
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sessionEstablishment, err := readerSession.SessionEstablishment([]*mdoc.ItemsRequest{{
+				DocType: "docType1",
+				NameSpaces: mdoc.NameSpaces{
+					"nameSpace1": {"dataElementIdentifier1": false, "dataElementIdentifier2": false},
+				},
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sessionData, err := holderSession.HandleSessionEstablishment(sessionEstablishment, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !consentReaderCertificate.Equal(readerAuthority.RootCertificate) {
+				t.Fatal("expected reader certificate passed to consent")
+			}
+
+			deviceResponse, err := readerSession.HandleSessionData(sessionData, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantDocumentError {
+				if len(deviceResponse.Documents) != 0 || len(deviceResponse.DocumentErrors) != 1 {
+					t.Fatal("expected document error")
+				}
+			} else {
+				if len(deviceResponse.Documents) != 1 {
+					t.Fatalf("expected 1 document, got %d", len(deviceResponse.Documents))
+				}
+				issuerSigned := deviceResponse.Documents[0].IssuerSigned
+				if len(issuerSigned["nameSpace1"]) != len(tt.wantItems) {
+					t.Fatalf("expected %d items, got %d", len(tt.wantItems), len(issuerSigned["nameSpace1"]))
+				}
+				for _, wantItem := range tt.wantItems {
+					if _, ok := issuerSigned.Get("nameSpace1", wantItem); !ok {
+						t.Fatalf("expected %s", wantItem)
+					}
+				}
+			}
+
+			if _, err = holderSession.HandleSessionData(readerSession.Terminate(), now); !errors.Is(err, session.ErrSessionTermination) {
+				t.Fatalf("expected %v, got %v", session.ErrSessionTermination, err)
+			}
+			if holderSession.State() != holder.SessionStateTerminated {
+				t.Fatal("expected terminated session")
+			}
+		})
+	}
+}
+
+func Test_HolderSession_Errors(t *testing.T) {
+	errConsent := errors.New("consent")
+
+	tests := []struct {
+		name        string
+		trustReader bool
+		consentErr  error
+		wantErr     error
+		wantStatus  session.SessionStatus
+	}{
+		{
+			name:        "ReaderAuth",
+			trustReader: false,
+			wantStatus:  session.SessionStatusSessionTermination,
+		},
+		{
+			name:        "Consent",
+			trustReader: true,
+			consentErr:  errConsent,
+			wantErr:     errConsent,
+			wantStatus:  session.SessionStatusSessionTermination,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rand := testutil.NewDeterministicRand(t)
+			now := time.UnixMilli(1500)
+
+			iacaCertificate, iacaKey := newIACA(t, rand)
+			issuerAuthority := newIssuerAuthority(t, rand, iacaCertificate, iacaKey)
+			readerAuthority, readerRootCertificate := newReaderAuthority(t, rand)
+
+			sDeviceKey, err := cipher_suite.GeneratePrivateKey(rand, mdoc.CurveP256, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			issuerSigned := newIssuerSigned(
+				t, rand, issuerAuthority,
+				"docType1",
+				map[mdoc.NameSpace]map[mdoc.DataElementIdentifier]mdoc.DataElementValue{
+					"nameSpace1": {"dataElementIdentifier1": "value1"},
+				},
+				&sDeviceKey.PublicKey,
+			)
+
+			var rootCertificates []*x509.Certificate
+			if tt.trustReader {
+				rootCertificates = []*x509.Certificate{readerRootCertificate}
+			}
+
+			holderSession, err := holder.NewSession(
+				rand,
+				mdoc.CurveP256,
+				nil,
+				mdoc.QRHandover{},
+				map[mdoc.DocType]mdoc.IssuerSigned{"docType1": *issuerSigned},
+				sDeviceKey,
+				rootCertificates,
+				nil,
+				func(docType mdoc.DocType, nameSpaces mdoc.NameSpaces, readerCertificate *x509.Certificate) (mdoc.NameSpaces, error) {
+					return nameSpaces, tt.consentErr
+				},
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			readerSession, err := reader.NewSession(
+				rand,
+				holderSession.DeviceEngagementBytes(),
+				mdoc.QRHandover{},
+				readerAuthority,
+				[]*x509.Certificate{iacaCertificate},
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sessionEstablishment, err := readerSession.SessionEstablishment([]*mdoc.ItemsRequest{{
+				DocType:    "docType1",
+				NameSpaces: mdoc.NameSpaces{"nameSpace1": {"dataElementIdentifier1": false}},
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = holderSession.HandleSessionEstablishment(sessionEstablishment, now)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if status := session.StatusForError(err); status != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, status)
+			}
+			if holderSession.State() != holder.SessionStateTerminated {
+				t.Fatal("expected terminated session")
+			}
+		})
+	}
+}
+
+func Test_Session_RetainCounterOnFailure(t *testing.T) {
+	tests := []struct {
+		name                   string
+		retainCounterOnFailure bool
+	}{
+		{name: "AdvanceCounter", retainCounterOnFailure: false},
+		{name: "RetainCounter", retainCounterOnFailure: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rand := testutil.NewDeterministicRand(t)
+			now := time.UnixMilli(1500)
+
+			iacaCertificate, iacaKey := newIACA(t, rand)
+			issuerAuthority := newIssuerAuthority(t, rand, iacaCertificate, iacaKey)
+
+			sDeviceKey, err := cipher_suite.GeneratePrivateKey(rand, mdoc.CurveP256, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			issuerSigned := newIssuerSigned(
+				t, rand, issuerAuthority,
+				"docType1",
+				map[mdoc.NameSpace]map[mdoc.DataElementIdentifier]mdoc.DataElementValue{
+					"nameSpace1": {"dataElementIdentifier1": "value1"},
+				},
+				&sDeviceKey.PublicKey,
+			)
+
+			options := &session.SessionEncryptionOptions{RetainCounterOnFailure: tt.retainCounterOnFailure}
+
+			holderSession, err := holder.NewSession(
+				rand,
+				mdoc.CurveP256,
+				nil,
+				mdoc.QRHandover{},
+				map[mdoc.DocType]mdoc.IssuerSigned{"docType1": *issuerSigned},
+				sDeviceKey,
+				nil,
+				&mdoc.VerifierPolicy{},
+				func(docType mdoc.DocType, nameSpaces mdoc.NameSpaces, readerCertificate *x509.Certificate) (mdoc.NameSpaces, error) {
+					return nameSpaces, nil
+				},
+				options,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			readerSession, err := reader.NewSession(
+				rand,
+				holderSession.DeviceEngagementBytes(),
+				mdoc.QRHandover{},
+				nil,
+				[]*x509.Certificate{iacaCertificate},
+				nil,
+				options,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			itemsRequests := []*mdoc.ItemsRequest{{
+				DocType:    "docType1",
+				NameSpaces: mdoc.NameSpaces{"nameSpace1": {"dataElementIdentifier1": false}},
+			}}
+
+			sessionEstablishment, err := readerSession.SessionEstablishment(itemsRequests)
+			if err != nil {
+				t.Fatal(err)
+			}
+			response, err := holderSession.HandleSessionEstablishment(sessionEstablishment, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = readerSession.HandleSessionData(response, now); err != nil {
+				t.Fatal(err)
+			}
+
+			request, err := readerSession.SessionData(itemsRequests)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			corruptedRequest := &session.SessionData{Data: bytes.Clone(request.Data)}
+			corruptedRequest.Data[0] ^= 1
+			if _, err = holderSession.HandleSessionData(corruptedRequest, now); !errors.Is(err, session.ErrSessionEncryption) {
+				t.Fatalf("expected %v, got %v", session.ErrSessionEncryption, err)
+			}
+
+			if !tt.retainCounterOnFailure {
+				if holderSession.State() != holder.SessionStateTerminated {
+					t.Fatal("expected terminated session")
+				}
+				return
+			}
+
+			response, err = holderSession.HandleSessionData(request, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			corruptedResponse := &session.SessionData{Data: bytes.Clone(response.Data)}
+			corruptedResponse.Data[0] ^= 1
+			if _, err = readerSession.HandleSessionData(corruptedResponse, now); !errors.Is(err, session.ErrSessionEncryption) {
+				t.Fatalf("expected %v, got %v", session.ErrSessionEncryption, err)
+			}
+			if readerSession.State() != reader.SessionStateEstablished {
+				t.Fatal("expected established session")
+			}
+
+			if _, err = readerSession.HandleSessionData(response, now); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func Test_Session_DecodeLimits(t *testing.T) {
+	tests := []struct {
+		name         string
+		holderLimits *mdoc.DecodeLimits
+		readerLimits *mdoc.DecodeLimits
+	}{
+		{name: "Holder", holderLimits: &mdoc.DecodeLimits{MaxNestedLevels: 1}},
+		{name: "Reader", readerLimits: &mdoc.DecodeLimits{MaxByteStringLength: 128}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rand := testutil.NewDeterministicRand(t)
+			now := time.UnixMilli(1500)
+
+			iacaCertificate, iacaKey := newIACA(t, rand)
+			issuerAuthority := newIssuerAuthority(t, rand, iacaCertificate, iacaKey)
+
+			sDeviceKey, err := cipher_suite.GeneratePrivateKey(rand, mdoc.CurveP256, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			issuerSigned := newIssuerSigned(
+				t, rand, issuerAuthority,
+				"docType1",
+				map[mdoc.NameSpace]map[mdoc.DataElementIdentifier]mdoc.DataElementValue{
+					"nameSpace1": {"dataElementIdentifier1": "value1"},
+				},
+				&sDeviceKey.PublicKey,
+			)
+
+			holderSession, err := holder.NewSession(
+				rand,
+				mdoc.CurveP256,
+				nil,
+				mdoc.QRHandover{},
+				map[mdoc.DocType]mdoc.IssuerSigned{"docType1": *issuerSigned},
+				sDeviceKey,
+				nil,
+				&mdoc.VerifierPolicy{},
+				func(docType mdoc.DocType, nameSpaces mdoc.NameSpaces, readerCertificate *x509.Certificate) (mdoc.NameSpaces, error) {
+					return nameSpaces, nil
+				},
+				&session.SessionEncryptionOptions{DecodeLimits: tt.holderLimits},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			readerSession, err := reader.NewSession(
+				rand,
+				holderSession.DeviceEngagementBytes(),
+				mdoc.QRHandover{},
+				nil,
+				[]*x509.Certificate{iacaCertificate},
+				nil,
+				&session.SessionEncryptionOptions{DecodeLimits: tt.readerLimits},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sessionEstablishment, err := readerSession.SessionEstablishment([]*mdoc.ItemsRequest{{
+				DocType:    "docType1",
+				NameSpaces: mdoc.NameSpaces{"nameSpace1": {"dataElementIdentifier1": false}},
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			response, err := holderSession.HandleSessionEstablishment(sessionEstablishment, now)
+			if tt.holderLimits != nil {
+				if !errors.Is(err, session.ErrCBORDecoding) {
+					t.Fatalf("expected %v, got %v", session.ErrCBORDecoding, err)
+				}
+				if holderSession.State() != holder.SessionStateTerminated {
+					t.Fatal("expected terminated session")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = readerSession.HandleSessionData(response, now); !errors.Is(err, session.ErrCBORDecoding) {
+				t.Fatalf("expected %v, got %v", session.ErrCBORDecoding, err)
+			}
+			if readerSession.State() != reader.SessionStateTerminated {
+				t.Fatal("expected terminated session")
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package spec
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/alex-richards/go-mdoc/cipher_suite"
+	"github.com/alex-richards/go-mdoc/internal/cbor"
+	"github.com/alex-richards/go-mdoc/internal/testutil"
+	"github.com/google/go-cmp/cmp"
+	"github.com/veraison/go-cose"
+)
+
+func Test_PublicKey_CBOR_RoundTrip(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	privateKey, err := cipher_suite.GeneratePrivateKey(rand, mdoc.CurveP256, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// go-mdoc has no Brainpool private keys, so the Brainpool keys use the curve generator
+	// as a public point.
+	newBrainpoolPublicKey := func(curve mdoc.Curve) *mdoc.PublicKey {
+		params, err := curve.Params()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ellipticParams := params.EllipticCurve().Params()
+		publicKey, err := mdoc.NewPublicKey(&ecdsa.PublicKey{
+			Curve: params.EllipticCurve(),
+			X:     ellipticParams.Gx,
+			Y:     ellipticParams.Gy,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return publicKey
+	}
+
+	tests := []struct {
+		curve         mdoc.Curve
+		publicKey     *mdoc.PublicKey
+		wantCOSECurve cose.Curve
+	}{
+		{curve: mdoc.CurveP256, publicKey: &privateKey.PublicKey, wantCOSECurve: cose.CurveP256},
+		{curve: mdoc.CurveBrainpoolP256r1, publicKey: newBrainpoolPublicKey(mdoc.CurveBrainpoolP256r1), wantCOSECurve: mdoc.COSECurveBrainpoolP256r1},
+		{curve: mdoc.CurveBrainpoolP320r1, publicKey: newBrainpoolPublicKey(mdoc.CurveBrainpoolP320r1), wantCOSECurve: mdoc.COSECurveBrainpoolP320r1},
+		{curve: mdoc.CurveBrainpoolP384r1, publicKey: newBrainpoolPublicKey(mdoc.CurveBrainpoolP384r1), wantCOSECurve: mdoc.COSECurveBrainpoolP384r1},
+		{curve: mdoc.CurveBrainpoolP512r1, publicKey: newBrainpoolPublicKey(mdoc.CurveBrainpoolP512r1), wantCOSECurve: mdoc.COSECurveBrainpoolP512r1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.curve.Name(), func(t *testing.T) {
+			data, err := cbor.Marshal(tt.publicKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			publicKey := new(mdoc.PublicKey)
+			if err = cbor.Unmarshal(data, publicKey); err != nil {
+				t.Fatal(err)
+			}
+
+			if publicKey.Params[cose.KeyLabelEC2Curve] != tt.wantCOSECurve {
+				t.Fatalf("expected COSE curve %d, got %v", tt.wantCOSECurve, publicKey.Params[cose.KeyLabelEC2Curve])
+			}
+
+			curve, err := publicKey.Curve()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if curve != tt.curve {
+				t.Fatalf("expected %s, got %s", tt.curve, curve)
+			}
+
+			if diff := cmp.Diff(tt.publicKey, publicKey); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
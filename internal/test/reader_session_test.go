@@ -0,0 +1,306 @@
+package spec
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/alex-richards/go-mdoc/cipher_suite"
+	"github.com/alex-richards/go-mdoc/holder"
+	mdoccbor "github.com/alex-richards/go-mdoc/internal/cbor"
+	"github.com/alex-richards/go-mdoc/internal/testutil"
+	"github.com/alex-richards/go-mdoc/reader"
+	"github.com/alex-richards/go-mdoc/session"
+	"github.com/fxamacker/cbor/v2"
+)
+
+func Test_ReaderSession(t *testing.T) {
+	tests := []struct {
+		name string
+		sign bool
+	}{
+		{name: "DeviceSignature", sign: true},
+		{name: "DeviceMAC", sign: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rand := testutil.NewDeterministicRand(t)
+			now := time.UnixMilli(1500)
+
+			iacaCertificate, iacaKey := newIACA(t, rand)
+			issuerAuthority := newIssuerAuthority(t, rand, iacaCertificate, iacaKey)
+			readerAuthority, readerRootCertificate := newReaderAuthority(t, rand)
+
+			sDeviceKey, err := cipher_suite.GeneratePrivateKey(rand, mdoc.CurveP256, tt.sign)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			issuerSigned := newIssuerSigned(
+				t, rand, issuerAuthority,
+				"docType1",
+				map[mdoc.NameSpace]map[mdoc.DataElementIdentifier]mdoc.DataElementValue{
+					"nameSpace1": {"dataElementIdentifier1": "value1"},
+				},
+				&sDeviceKey.PublicKey,
+			)
+
+			eDeviceKey, err := cipher_suite.GeneratePrivateKey(rand, mdoc.CurveP256, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			deviceEngagement, err := mdoc.NewDeviceEngagementBLE(&eDeviceKey.PublicKey, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			deviceEngagementBytes, err := cbor.Marshal(deviceEngagement)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			readerSession, err := reader.NewSession(
+				rand,
+				deviceEngagementBytes,
+				mdoc.QRHandover{},
+				readerAuthority,
+				[]*x509.Certificate{iacaCertificate},
+				nil,
+				nil,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sessionEstablishment, err := readerSession.SessionEstablishment([]*mdoc.ItemsRequest{{
+				DocType: "docType1",
+				NameSpaces: mdoc.NameSpaces{
+					"nameSpace1": {"dataElementIdentifier1": false},
+				},
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if readerSession.State() != reader.SessionStateEstablished {
+				t.Fatal("expected established session")
+			}
+
+			sessionEstablishmentBytes, err := cbor.Marshal(sessionEstablishment)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			receivedSessionEstablishment := new(session.SessionEstablishment)
+			if err = cbor.Unmarshal(sessionEstablishmentBytes, receivedSessionEstablishment); err != nil {
+				t.Fatal(err)
+			}
+
+			eReaderKey, err := receivedSessionEstablishment.EReaderKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			taggedDeviceEngagementBytes, err := mdoccbor.NewTaggedEncodedCBOR(deviceEngagementBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sessionTranscript := &mdoc.SessionTranscript{
+				DeviceEngagementBytes: taggedDeviceEngagementBytes,
+				EReaderKeyBytes:       &receivedSessionEstablishment.EReaderKeyBytes,
+				Handover:              mdoc.QRHandover{},
+			}
+
+			sessionTranscriptBytes, err := mdoc.NewSessionTranscriptBytes(sessionTranscript)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			holderSessionEncryption, err := holder.NewSessionEncryption(eDeviceKey, eReaderKey, sessionTranscriptBytes, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			deviceRequestBytes, err := holderSessionEncryption.Decrypt(receivedSessionEstablishment.Data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			deviceRequest := new(mdoc.DeviceRequest)
+			if err = cbor.Unmarshal(deviceRequestBytes, deviceRequest); err != nil {
+				t.Fatal(err)
+			}
+
+			if err = deviceRequest.Verify([]*x509.Certificate{readerRootCertificate}, now, sessionTranscript); err != nil {
+				t.Fatal(err)
+			}
+
+			deviceResponse, err := holder.NewDeviceResponse(
+				deviceRequest,
+				map[mdoc.DocType]mdoc.IssuerSigned{"docType1": *issuerSigned},
+				nil,
+				rand,
+				sDeviceKey,
+				sessionTranscript,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			deviceResponseBytes, err := cbor.Marshal(deviceResponse)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			deviceResponseCipherText, err := holderSessionEncryption.Encrypt(deviceResponseBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			receivedDeviceResponse, err := readerSession.HandleSessionData(&session.SessionData{
+				Data:   deviceResponseCipherText,
+				Status: session.SessionStatusSessionTermination,
+			}, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(receivedDeviceResponse.Documents) != 1 {
+				t.Fatalf("expected 1 document, got %d", len(receivedDeviceResponse.Documents))
+			}
+			if _, ok := receivedDeviceResponse.Documents[0].IssuerSigned.Get("nameSpace1", "dataElementIdentifier1"); !ok {
+				t.Fatal("expected dataElementIdentifier1")
+			}
+
+			if readerSession.State() != reader.SessionStateTerminated {
+				t.Fatal("expected terminated session")
+			}
+		})
+	}
+}
+
+func Test_ReaderSession_HandleSessionData_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		sessionData session.SessionData
+		want        error
+	}{
+		{
+			name:        "BadCipherText",
+			sessionData: session.SessionData{Data: []byte{1, 2, 3, 4}},
+			want:        session.ErrSessionEncryption,
+		},
+		{
+			name:        "StatusOnly",
+			sessionData: session.SessionData{Status: session.SessionStatusErrorSessionEncryption},
+			want:        session.ErrSessionEncryption,
+		},
+		{
+			name:        "Termination",
+			sessionData: session.SessionData{Status: session.SessionStatusSessionTermination},
+			want:        session.ErrSessionTermination,
+		},
+		{
+			name:        "Empty",
+			sessionData: session.SessionData{},
+			want:        reader.ErrMissingSessionData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rand := testutil.NewDeterministicRand(t)
+
+			eDeviceKey, err := cipher_suite.GeneratePrivateKey(rand, mdoc.CurveP256, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			deviceEngagement, err := mdoc.NewDeviceEngagementBLE(&eDeviceKey.PublicKey, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			deviceEngagementBytes, err := cbor.Marshal(deviceEngagement)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			readerSession, err := reader.NewSession(rand, deviceEngagementBytes, mdoc.QRHandover{}, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = readerSession.SessionEstablishment(nil); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = readerSession.HandleSessionData(&tt.sessionData, time.UnixMilli(1500))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+
+			if readerSession.State() != reader.SessionStateTerminated {
+				t.Fatal("expected terminated session")
+			}
+
+			if _, err = readerSession.SessionData(nil); !errors.Is(err, reader.ErrUnexpectedSessionState) {
+				t.Fatalf("expected %v, got %v", reader.ErrUnexpectedSessionState, err)
+			}
+		})
+	}
+}
+
+func Test_ReaderSession_UnsupportedCipherSuite(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	eDeviceKey, err := cipher_suite.GeneratePrivateKey(rand, mdoc.CurveP256, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deviceEngagement, err := mdoc.NewDeviceEngagementBLE(&eDeviceKey.PublicKey, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deviceEngagement.Security.CipherSuiteIdentifier = -1
+
+	deviceEngagementBytes, err := cbor.Marshal(deviceEngagement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = reader.NewSession(rand, deviceEngagementBytes, mdoc.QRHandover{}, nil, nil, nil, nil)
+	if !errors.Is(err, session.ErrUnsupportedCipherSuite) {
+		t.Fatalf("expected %v, got %v", session.ErrUnsupportedCipherSuite, err)
+	}
+}
+
+func Test_ReaderSession_DeviceEngagement_DecodeLimits(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	eDeviceKey, err := cipher_suite.GeneratePrivateKey(rand, mdoc.CurveP256, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deviceEngagement, err := mdoc.NewDeviceEngagementBLE(&eDeviceKey.PublicKey, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deviceEngagementBytes, err := cbor.Marshal(deviceEngagement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := &session.SessionEncryptionOptions{DecodeLimits: &mdoc.DecodeLimits{MaxNestedLevels: 1}}
+	_, err = reader.NewSession(rand, deviceEngagementBytes, mdoc.QRHandover{}, nil, nil, nil, options)
+	if !errors.Is(err, mdoc.ErrMaxNestedLevels) {
+		t.Fatalf("expected %v, got %v", mdoc.ErrMaxNestedLevels, err)
+	}
+}
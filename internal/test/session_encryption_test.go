@@ -79,19 +79,22 @@ func Test_SessionEncryption_RoundTrip(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	readerSessionEncryption, err := reader.NewSessionEncryption(eReaderKey, &eDeviceKey.PublicKey, sessionTranscriptBytes)
+	readerSessionEncryption, err := reader.NewSessionEncryption(eReaderKey, &eDeviceKey.PublicKey, sessionTranscriptBytes, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	deviceSessionEncryption, err := holder.NewSessionEncryption(eDeviceKey, &eReaderKey.PublicKey, sessionTranscriptBytes)
+	deviceSessionEncryption, err := holder.NewSessionEncryption(eDeviceKey, &eReaderKey.PublicKey, sessionTranscriptBytes, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	clearText := []byte("lorem ipsum")
 
-	readerCipherText1 := readerSessionEncryption.Encrypt(clearText)
+	readerCipherText1, err := readerSessionEncryption.Encrypt(clearText)
+	if err != nil {
+		t.Fatal(err)
+	}
 	deviceClearText1, err := deviceSessionEncryption.Decrypt(readerCipherText1)
 	if err != nil {
 		t.Fatal(err)
@@ -100,7 +103,10 @@ func Test_SessionEncryption_RoundTrip(t *testing.T) {
 		t.Fatal()
 	}
 
-	deviceCipherText1 := deviceSessionEncryption.Encrypt(clearText)
+	deviceCipherText1, err := deviceSessionEncryption.Encrypt(clearText)
+	if err != nil {
+		t.Fatal(err)
+	}
 	readerClearText1, err := readerSessionEncryption.Decrypt(deviceCipherText1)
 	if err != nil {
 		t.Fatal(err)
@@ -109,7 +115,10 @@ func Test_SessionEncryption_RoundTrip(t *testing.T) {
 		t.Fatal()
 	}
 
-	readerCipherText2 := readerSessionEncryption.Encrypt(clearText)
+	readerCipherText2, err := readerSessionEncryption.Encrypt(clearText)
+	if err != nil {
+		t.Fatal(err)
+	}
 	deviceClearText2, err := deviceSessionEncryption.Decrypt(readerCipherText2)
 	if err != nil {
 		t.Fatal()
@@ -121,7 +130,10 @@ func Test_SessionEncryption_RoundTrip(t *testing.T) {
 		t.Fatal()
 	}
 
-	deviceCipherText2 := deviceSessionEncryption.Encrypt(clearText)
+	deviceCipherText2, err := deviceSessionEncryption.Encrypt(clearText)
+	if err != nil {
+		t.Fatal(err)
+	}
 	readerClearText2, err := readerSessionEncryption.Decrypt(deviceCipherText2)
 	if err != nil {
 		t.Fatal()
@@ -0,0 +1,187 @@
+package spec
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/alex-richards/go-mdoc/cipher_suite"
+	mdocecdh "github.com/alex-richards/go-mdoc/cipher_suite/ecdh"
+	mdocecdsa "github.com/alex-richards/go-mdoc/cipher_suite/ecdsa"
+	"github.com/alex-richards/go-mdoc/holder"
+	"github.com/alex-richards/go-mdoc/internal/cbor"
+	"github.com/alex-richards/go-mdoc/internal/testutil"
+	"github.com/google/go-cmp/cmp"
+)
+
+// opaqueSigner hides the type of the private key, as a KMS or HSM client would.
+type opaqueSigner struct {
+	signer crypto.Signer
+}
+
+func (s opaqueSigner) Public() crypto.PublicKey {
+	return s.signer.Public()
+}
+
+func (s opaqueSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.signer.Sign(rand, digest, opts)
+}
+
+// invalidSigner returns a signature which is not ASN.1.
+type invalidSigner struct {
+	opaqueSigner
+}
+
+func (s invalidSigner) Sign(io.Reader, []byte, crypto.SignerOpts) ([]byte, error) {
+	return []byte{1, 2, 3, 4}, nil
+}
+
+func Test_NewSigner(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	newECDSA := func(curve mdoc.Curve) crypto.Signer {
+		params, err := curve.Params()
+		if err != nil {
+			t.Fatal(err)
+		}
+		privateKey, err := ecdsa.GenerateKey(params.EllipticCurve(), rand)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return privateKey
+	}
+
+	_, ed25519PrivateKey, err := ed25519.GenerateKey(rand)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		curve  mdoc.Curve
+		signer crypto.Signer
+	}{
+		{name: "P256", curve: mdoc.CurveP256, signer: newECDSA(mdoc.CurveP256)},
+		{name: "P384", curve: mdoc.CurveP384, signer: newECDSA(mdoc.CurveP384)},
+		{name: "P521", curve: mdoc.CurveP521, signer: newECDSA(mdoc.CurveP521)},
+		{name: "Ed25519", curve: mdoc.CurveEd25519, signer: ed25519PrivateKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privateKey, err := cipher_suite.NewSigner(opaqueSigner{tt.signer})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if curve := privateKey.Signer.Curve(); curve != tt.curve {
+				t.Fatalf("expected %s, got %s", tt.curve, curve)
+			}
+
+			wantPublicKey, err := mdoc.NewPublicKey(tt.signer.Public())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(*wantPublicKey, privateKey.PublicKey); diff != "" {
+				t.Fatal(diff)
+			}
+
+			eReaderKey, err := mdocecdh.GeneratePrivateKey(rand, mdoc.CurveP256)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sessionTranscript := newSessionTranscript(t, &eReaderKey.PublicKey)
+			sessionTranscriptBytes, err := mdoc.NewSessionTranscriptBytes(sessionTranscript)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// repeat to cover r and s with leading zeros
+			for i := 0; i < 16; i++ {
+				deviceAuthenticationBytes := &cbor.TaggedEncodedCBOR{TaggedValue: []byte{byte(i)}}
+
+				deviceAuth, err := holder.NewDeviceAuth(rand, privateKey, sessionTranscript, deviceAuthenticationBytes)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = deviceAuth.Verify(&privateKey.PublicKey, eReaderKey, sessionTranscriptBytes, deviceAuthenticationBytes)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func Test_NewSigner_Errors(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	ecdsaPrivateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaPrivateKey, err := rsa.GenerateKey(rand, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Unsupported", func(t *testing.T) {
+		if _, err := cipher_suite.NewSigner(rsaPrivateKey); !errors.Is(err, cipher_suite.ErrUnsupportedPrivateKey) {
+			t.Fatalf("expected %v, got %v", cipher_suite.ErrUnsupportedPrivateKey, err)
+		}
+	})
+
+	t.Run("Brainpool", func(t *testing.T) {
+		params, err := mdoc.CurveBrainpoolP256r1.Params()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ellipticCurve := params.EllipticCurve()
+
+		// The key is never used, so the generator stands in for the public point.
+		brainpoolPrivateKey := &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{
+				Curve: ellipticCurve,
+				X:     ellipticCurve.Params().Gx,
+				Y:     ellipticCurve.Params().Gy,
+			},
+			D: big.NewInt(1),
+		}
+
+		if _, err = cipher_suite.NewSigner(brainpoolPrivateKey); !errors.Is(err, mdoc.ErrUnsupportedCurve) {
+			t.Fatalf("expected %v, got %v", mdoc.ErrUnsupportedCurve, err)
+		}
+		if _, err = cipher_suite.NewSigner(opaqueSigner{brainpoolPrivateKey}); !errors.Is(err, mdoc.ErrUnsupportedCurve) {
+			t.Fatalf("expected %v, got %v", mdoc.ErrUnsupportedCurve, err)
+		}
+		if _, err = cipher_suite.NewPrivateKey(mdoc.CurveBrainpoolP256r1, brainpoolPrivateKey); !errors.Is(err, mdoc.ErrUnsupportedCurve) {
+			t.Fatalf("expected %v, got %v", mdoc.ErrUnsupportedCurve, err)
+		}
+		for _, sign := range []bool{true, false} {
+			if _, err = cipher_suite.GeneratePrivateKey(rand, mdoc.CurveBrainpoolP256r1, sign); !errors.Is(err, mdoc.ErrUnsupportedCurve) {
+				t.Fatalf("expected %v, got %v", mdoc.ErrUnsupportedCurve, err)
+			}
+		}
+	})
+
+	t.Run("Invalid Signature", func(t *testing.T) {
+		privateKey, err := cipher_suite.NewSigner(invalidSigner{opaqueSigner{ecdsaPrivateKey}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = privateKey.Signer.Sign(rand, []byte{1, 2, 3, 4}); !errors.Is(err, mdocecdsa.ErrInvalidSignature) {
+			t.Fatalf("expected %v, got %v", mdocecdsa.ErrInvalidSignature, err)
+		}
+	})
+}
@@ -503,7 +503,7 @@ func TestSpec_ReaderSessionEncryption(t *testing.T) {
 		&mdoccbor.TaggedEncodedCBOR{
 			TaggedValue:   sessionTranscriptBytes,
 			UntaggedValue: nil,
-		})
+		}, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -639,19 +639,36 @@ func TestSpec_DeviceResponse_DeviceAuth_Verify(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	deviceAuthenticationEncoded := testutil.DecodeHex(t, DeviceAuthenticationHex)
-	deviceAuthenticationBytes, err := mdoccbor.NewTaggedEncodedCBOR(deviceAuthenticationEncoded)
+	sessionTranscriptTagged := testutil.DecodeHex(t, SessionTranscriptHex)
+
+	var sessionTranscriptBytes mdoccbor.TaggedEncodedCBOR
+	if err := cbor.Unmarshal(sessionTranscriptTagged, &sessionTranscriptBytes); err != nil {
+		t.Fatal(err)
+	}
+
+	var sessionTranscript mdoc.SessionTranscript
+	if err := cbor.Unmarshal(sessionTranscriptBytes.UntaggedValue, &sessionTranscript); err != nil {
+		t.Fatal(err)
+	}
+
+	document := deviceResponse.Documents[0]
+
+	deviceAuthenticationBytes, err := mdoc.NewDeviceAuthenticationBytes(
+		&sessionTranscript,
+		document.DocType,
+		&document.DeviceSigned.NameSpacesBytes,
+	)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	err = deviceResponse.Documents[0].DeviceSigned.DeviceAuth.Verify(
+	err = document.DeviceSigned.DeviceAuth.Verify(
 		spec_SDeviceKey(t),
+		spec_EReaderKeyPrivate(t),
+		&sessionTranscriptBytes,
 		deviceAuthenticationBytes,
 	)
-
-	// TODO requires CoseMAC0
-	if err != mdoc.ErrMACAuthNotSupported {
+	if err != nil {
 		t.Fatal(err)
 	}
 }
@@ -0,0 +1,294 @@
+package spec
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/alex-richards/go-mdoc/cipher_suite"
+	"github.com/alex-richards/go-mdoc/holder"
+	"github.com/alex-richards/go-mdoc/internal/testutil"
+	"github.com/alex-richards/go-mdoc/schema"
+	gocose "github.com/veraison/go-cose"
+)
+
+func Test_Document_VerifyReport(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	iacaCertificate, iacaKey := newIACA(t, rand)
+	issuerAuthority := newIssuerAuthority(t, rand, iacaCertificate, iacaKey)
+	rootCertificates := []*x509.Certificate{iacaCertificate}
+
+	sDeviceKey, err := cipher_suite.GeneratePrivateKey(rand, mdoc.CurveP256, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eReaderKey, err := cipher_suite.GeneratePrivateKey(rand, mdoc.CurveP256, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessionTranscript := newSessionTranscript(t, &eReaderKey.PublicKey)
+
+	policy := mdoc.DefaultVerifierPolicy()
+
+	newDocument := func(t *testing.T, deviceNameSpaces mdoc.DeviceNameSpaces) *mdoc.Document {
+		issuerSigned := newIssuerSigned(
+			t, rand, issuerAuthority,
+			"docType1",
+			map[mdoc.NameSpace]map[mdoc.DataElementIdentifier]mdoc.DataElementValue{
+				"nameSpace1": {"dataElementIdentifier1": "value1"},
+			},
+			&sDeviceKey.PublicKey,
+		)
+
+		deviceSigned, err := holder.NewDeviceSigned("docType1", deviceNameSpaces, rand, sDeviceKey, sessionTranscript)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return &mdoc.Document{
+			DocType:      "docType1",
+			IssuerSigned: *issuerSigned,
+			DeviceSigned: *deviceSigned,
+		}
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		document := newDocument(t, mdoc.DeviceNameSpaces{})
+
+		report, err := document.VerifyReport(policy, rootCertificates, time.UnixMilli(1500), eReaderKey, sessionTranscript)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = report.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if !report.Digests.Valid("nameSpace1", "dataElementIdentifier1") {
+			t.Fatal("expected valid digest")
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		document := newDocument(t, mdoc.DeviceNameSpaces{})
+
+		report, err := document.VerifyReport(policy, rootCertificates, time.UnixMilli(2001), eReaderKey, sessionTranscript)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !errors.Is(report.Validity, mdoc.ErrDocumentExpired) {
+			t.Fatalf("expected %v, got %v", mdoc.ErrDocumentExpired, report.Validity)
+		}
+		if !report.Digests.Valid("nameSpace1", "dataElementIdentifier1") {
+			t.Fatal("expected digests to still be checked")
+		}
+	})
+
+	t.Run("ClockSkew", func(t *testing.T) {
+		document := newDocument(t, mdoc.DeviceNameSpaces{})
+
+		report, err := document.VerifyReport(&mdoc.VerifierPolicy{ClockSkew: 10 * time.Millisecond}, rootCertificates, time.UnixMilli(2001), eReaderKey, sessionTranscript)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Validity != nil {
+			t.Fatal(report.Validity)
+		}
+	})
+
+	t.Run("DocTypeMismatch", func(t *testing.T) {
+		document := newDocument(t, mdoc.DeviceNameSpaces{})
+		document.DocType = "docType2"
+
+		report, err := document.VerifyReport(policy, rootCertificates, time.UnixMilli(1500), eReaderKey, sessionTranscript)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !errors.Is(report.MobileSecurityObject, mdoc.ErrDocTypeMismatch) {
+			t.Fatalf("expected %v, got %v", mdoc.ErrDocTypeMismatch, report.MobileSecurityObject)
+		}
+		if err = report.Err(); !errors.Is(err, mdoc.ErrDocTypeMismatch) {
+			t.Fatalf("expected %v, got %v", mdoc.ErrDocTypeMismatch, err)
+		}
+	})
+
+	t.Run("PartialFailure", func(t *testing.T) {
+		document := newDocument(t, mdoc.DeviceNameSpaces{
+			"nameSpace1": {"dataElementIdentifier2": "value2"},
+		})
+
+		extraIssuerSignedItemBytes, err := mdoc.NewIssuerSignedItemBytes(rand, 99, "dataElementIdentifier3", "value3")
+		if err != nil {
+			t.Fatal(err)
+		}
+		document.IssuerSigned.NameSpaces["nameSpace1"] = append(
+			document.IssuerSigned.NameSpaces["nameSpace1"],
+			*extraIssuerSignedItemBytes,
+		)
+
+		report, err := document.VerifyReport(policy, rootCertificates, time.UnixMilli(1500), eReaderKey, sessionTranscript)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if report.IssuerAuth != nil {
+			t.Fatal(report.IssuerAuth)
+		}
+		if report.DeviceAuth != nil {
+			t.Fatal(report.DeviceAuth)
+		}
+		if !report.Digests.Valid("nameSpace1", "dataElementIdentifier1") {
+			t.Fatal("expected valid digest")
+		}
+		if err := report.Digests["nameSpace1"]["dataElementIdentifier3"]; !errors.Is(err, mdoc.ErrMissingDigest) {
+			t.Fatalf("expected %v, got %v", mdoc.ErrMissingDigest, err)
+		}
+		if err := report.KeyAuthorizations["nameSpace1"]["dataElementIdentifier2"]; !errors.Is(err, mdoc.ErrUnauthorizedDeviceNameSpace) {
+			t.Fatalf("expected %v, got %v", mdoc.ErrUnauthorizedDeviceNameSpace, err)
+		}
+
+		if err := report.Err(); !errors.Is(err, mdoc.ErrMissingDigest) {
+			t.Fatalf("expected %v, got %v", mdoc.ErrMissingDigest, err)
+		}
+		if err := document.Verify(rootCertificates, time.UnixMilli(1500), eReaderKey, sessionTranscript); !errors.Is(err, mdoc.ErrMissingDigest) {
+			t.Fatalf("expected %v, got %v", mdoc.ErrMissingDigest, err)
+		}
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		tests := []struct {
+			name                  string
+			digestID              mdoc.DigestID
+			dataElementIdentifier mdoc.DataElementIdentifier
+			wantErr               error
+		}{
+			{name: "DataElement", digestID: 99, dataElementIdentifier: "dataElementIdentifier1", wantErr: mdoc.ErrDuplicateDataElement},
+			{name: "DigestID", digestID: 0, dataElementIdentifier: "dataElementIdentifier2", wantErr: mdoc.ErrDuplicateDigestID},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				document := newDocument(t, mdoc.DeviceNameSpaces{})
+
+				validIssuerSignedItemBytes := document.IssuerSigned.NameSpaces["nameSpace1"][0]
+				validIssuerSignedItem, err := validIssuerSignedItemBytes.IssuerSignedItem()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				digestID := tt.digestID
+				if digestID == 0 {
+					digestID = validIssuerSignedItem.DigestID
+				}
+
+				// the tampered item comes first so that the valid one is checked last
+				tamperedIssuerSignedItemBytes, err := mdoc.NewIssuerSignedItemBytes(rand, digestID, tt.dataElementIdentifier, "tampered")
+				if err != nil {
+					t.Fatal(err)
+				}
+				document.IssuerSigned.NameSpaces["nameSpace1"] = []mdoc.IssuerSignedItemBytes{
+					*tamperedIssuerSignedItemBytes,
+					validIssuerSignedItemBytes,
+				}
+
+				if _, err = document.VerifyReport(policy, rootCertificates, time.UnixMilli(1500), eReaderKey, sessionTranscript); !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if err = document.Verify(rootCertificates, time.UnixMilli(1500), eReaderKey, sessionTranscript); !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+			})
+		}
+	})
+
+	t.Run("Schema", func(t *testing.T) {
+		document := newDocument(t, mdoc.DeviceNameSpaces{})
+
+		policy := &mdoc.VerifierPolicy{
+			Schema: schema.NewRegistry(&schema.NameSpace{
+				NameSpace: "nameSpace1",
+				Elements: []schema.Element{
+					{Identifier: "dataElementIdentifier1", Type: schema.TypeUint},
+				},
+			}),
+		}
+
+		report, err := document.VerifyReport(policy, rootCertificates, time.UnixMilli(1500), eReaderKey, sessionTranscript)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !report.Digests.Valid("nameSpace1", "dataElementIdentifier1") {
+			t.Fatal("expected valid digest")
+		}
+		if err := report.Types["nameSpace1"]["dataElementIdentifier1"]; !errors.Is(err, schema.ErrInvalidType) {
+			t.Fatalf("expected %v, got %v", schema.ErrInvalidType, err)
+		}
+		if err := report.Err(); !errors.Is(err, schema.ErrInvalidType) {
+			t.Fatalf("expected %v, got %v", schema.ErrInvalidType, err)
+		}
+	})
+
+	t.Run("Policy", func(t *testing.T) {
+		tests := []struct {
+			name             string
+			policy           *mdoc.VerifierPolicy
+			deviceNameSpaces mdoc.DeviceNameSpaces
+			wantErr          error
+		}{
+			{
+				name:   "Allowed",
+				policy: &mdoc.VerifierPolicy{DigestAlgorithms: []mdoc.DigestAlgorithm{mdoc.DigestAlgorithmSHA256}, Algorithms: []gocose.Algorithm{gocose.AlgorithmES256}, Curves: []mdoc.Curve{mdoc.CurveP256}, DocTypes: []mdoc.DocType{"docType1"}},
+			},
+			{
+				name:    "DigestAlgorithm",
+				policy:  &mdoc.VerifierPolicy{DigestAlgorithms: []mdoc.DigestAlgorithm{mdoc.DigestAlgorithmSHA512}},
+				wantErr: mdoc.ErrDigestAlgorithmNotAllowed,
+			},
+			{
+				name:    "Algorithm",
+				policy:  &mdoc.VerifierPolicy{Algorithms: []gocose.Algorithm{gocose.AlgorithmES384}},
+				wantErr: mdoc.ErrAlgorithmNotAllowed,
+			},
+			{
+				name:    "Curve",
+				policy:  &mdoc.VerifierPolicy{Curves: []mdoc.Curve{mdoc.CurveP384}},
+				wantErr: mdoc.ErrCurveNotAllowed,
+			},
+			{
+				name:    "DocType",
+				policy:  &mdoc.VerifierPolicy{DocTypes: []mdoc.DocType{"docType2"}},
+				wantErr: mdoc.ErrDocTypeNotAllowed,
+			},
+			{
+				name:             "RejectDeviceSigned",
+				policy:           &mdoc.VerifierPolicy{RejectDeviceSigned: true},
+				deviceNameSpaces: mdoc.DeviceNameSpaces{"nameSpace1": {"dataElementIdentifier2": "value2"}},
+				wantErr:          mdoc.ErrDeviceSignedNotAllowed,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				deviceNameSpaces := tt.deviceNameSpaces
+				if deviceNameSpaces == nil {
+					deviceNameSpaces = mdoc.DeviceNameSpaces{}
+				}
+				document := newDocument(t, deviceNameSpaces)
+
+				report, err := document.VerifyReport(tt.policy, rootCertificates, time.UnixMilli(1500), eReaderKey, sessionTranscript)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !errors.Is(report.Policy, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, report.Policy)
+				}
+				if tt.wantErr != nil && !errors.Is(report.Err(), tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, report.Err())
+				}
+			})
+		}
+	})
+}
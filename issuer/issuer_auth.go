@@ -29,7 +29,7 @@ func NewIssuerAuth(
 
 	sign1 := (*cose.Sign1Message)(issuerAuth)
 
-	err = sign1.Sign(rand, []byte{}, mdoc.CoseSigner{issuerAuthority.Signer})
+	err = sign1.Sign(rand, []byte{}, mdoc.CoseSigner{Signer: issuerAuthority.Signer})
 	if err != nil {
 		return nil, err
 	}
@@ -14,7 +14,6 @@ import (
 	cose2 "github.com/alex-richards/go-mdoc/internal/cose"
 	mdocX509 "github.com/alex-richards/go-mdoc/internal/x509"
 
-	"github.com/fxamacker/cbor/v2"
 	"github.com/veraison/go-cose"
 )
 
@@ -23,12 +22,20 @@ var (
 	ErrUnexpectedIntermediateCertificate = errors.New("mdoc: unexpected intermediate certificate")
 	ErrInvalidDocumentSignerCertificate  = errors.New("mdoc: invalid document signer certificate")
 	ErrDuplicateDigestID                 = errors.New("mdoc: duplicate digest ID")
+	ErrDocumentNotYetValid               = errors.New("mdoc: document not yet valid")
+	ErrDocumentExpired                   = errors.New("mdoc: document expired")
+	ErrUnsupportedMSOVersion             = errors.New("mdoc: unsupported mobile security object version")
+	ErrDocTypeMismatch                   = errors.New("mdoc: doc type does not match mobile security object")
+	ErrExpectedUpdatePassed              = errors.New("mdoc: mobile security object expected update has passed")
 )
 
 const (
 	MobileSecurityObjectVersion = "1.0"
 )
 
+// DefaultClockSkew is the tolerance applied to ValidityInfo when verifying with Verify.
+const DefaultClockSkew = time.Duration(0)
+
 const (
 	IACAMaxAgeYears          = 20
 	DocumentSignerMaxAgeDays = 457
@@ -41,10 +48,10 @@ var (
 type IssuerAuth cose.UntaggedSign1Message
 
 func (ia *IssuerAuth) MarshalCBOR() ([]byte, error) {
-	return cbor.Marshal((*cose.UntaggedSign1Message)(ia))
+	return cbor2.Marshal((*cose.UntaggedSign1Message)(ia))
 }
 func (ia *IssuerAuth) UnmarshalCBOR(data []byte) error {
-	return cbor.Unmarshal(data, (*cose.UntaggedSign1Message)(ia))
+	return cbor2.Unmarshal(data, (*cose.UntaggedSign1Message)(ia))
 }
 
 func (ia *IssuerAuth) Verify(rootCertificates []*x509.Certificate, now time.Time) error {
@@ -212,7 +219,7 @@ func ValidateDocumentSignerCertificate(documentSignerCertificate *x509.Certifica
 
 func (ia *IssuerAuth) MobileSecurityObjectBytes() (*cbor2.TaggedEncodedCBOR, error) {
 	mobileSecurityObjectBytes := new(cbor2.TaggedEncodedCBOR)
-	if err := cbor.Unmarshal(ia.Payload, mobileSecurityObjectBytes); err != nil {
+	if err := cbor2.Unmarshal(ia.Payload, mobileSecurityObjectBytes); err != nil {
 		return nil, err
 	}
 
@@ -226,7 +233,7 @@ func (ia *IssuerAuth) MobileSecurityObject() (*MobileSecurityObject, error) {
 	}
 
 	mobileSecurityObject := new(MobileSecurityObject)
-	if err = cbor.Unmarshal(mobileSecurityObjectBytes.UntaggedValue, mobileSecurityObject); err != nil {
+	if err = cbor2.Unmarshal(mobileSecurityObjectBytes.UntaggedValue, mobileSecurityObject); err != nil {
 		return nil, err
 	}
 
@@ -242,6 +249,14 @@ type MobileSecurityObject struct {
 	ValidityInfo    ValidityInfo     `cbor:"validityInfo"`
 }
 
+// Verify checks the MobileSecurityObject version is supported.
+func (mso *MobileSecurityObject) Verify() error {
+	if mso.Version != MobileSecurityObjectVersion {
+		return ErrUnsupportedMSOVersion
+	}
+	return nil
+}
+
 type NameSpaceDigests map[NameSpace]ValueDigests
 type ValueDigests map[DigestID]Digest
 type DigestID uint
@@ -297,3 +312,37 @@ type ValidityInfo struct {
 	ValidUntil     time.Time  `cbor:"validUntil"`
 	ExpectedUpdate *time.Time `cbor:"expectedUpdate,omitempty"`
 }
+
+type validityInfo ValidityInfo
+
+// MarshalCBOR encodes the times as tdate in UTC without fractional seconds.
+func (vi *ValidityInfo) MarshalCBOR() ([]byte, error) {
+	normalized := validityInfo{
+		Signed:     vi.Signed.UTC().Truncate(time.Second),
+		ValidFrom:  vi.ValidFrom.UTC().Truncate(time.Second),
+		ValidUntil: vi.ValidUntil.UTC().Truncate(time.Second),
+	}
+	if vi.ExpectedUpdate != nil {
+		expectedUpdate := vi.ExpectedUpdate.UTC().Truncate(time.Second)
+		normalized.ExpectedUpdate = &expectedUpdate
+	}
+	return cbor2.Marshal(&normalized)
+}
+
+// Verify checks now is within the validity window of the MobileSecurityObject, allowing
+// for clockSkew either side.
+func (vi *ValidityInfo) Verify(now time.Time, clockSkew time.Duration) error {
+	if now.Add(clockSkew).Before(vi.ValidFrom) {
+		return ErrDocumentNotYetValid
+	}
+	if now.Add(-clockSkew).After(vi.ValidUntil) {
+		return ErrDocumentExpired
+	}
+	return nil
+}
+
+// UpdateExpected reports whether the issuer expected the MobileSecurityObject to have been
+// updated by now.
+func (vi *ValidityInfo) UpdateExpected(now time.Time) bool {
+	return vi.ExpectedUpdate != nil && now.After(*vi.ExpectedUpdate)
+}
@@ -1 +1,129 @@
 package mdoc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	cbor2 "github.com/alex-richards/go-mdoc/internal/cbor"
+	"github.com/fxamacker/cbor/v2"
+)
+
+func Test_ValidityInfo_Verify(t *testing.T) {
+	validityInfo := ValidityInfo{
+		Signed:     time.UnixMilli(1000),
+		ValidFrom:  time.UnixMilli(1000),
+		ValidUntil: time.UnixMilli(2000),
+	}
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		clockSkew time.Duration
+		wantErr   error
+	}{
+		{name: "Valid", now: time.UnixMilli(1500)},
+		{name: "ValidFrom", now: time.UnixMilli(1000)},
+		{name: "ValidUntil", now: time.UnixMilli(2000)},
+		{name: "NotYetValid", now: time.UnixMilli(999), wantErr: ErrDocumentNotYetValid},
+		{name: "Expired", now: time.UnixMilli(2001), wantErr: ErrDocumentExpired},
+		{name: "NotYetValid ClockSkew", now: time.UnixMilli(990), clockSkew: 10 * time.Millisecond},
+		{name: "Expired ClockSkew", now: time.UnixMilli(2010), clockSkew: 10 * time.Millisecond},
+		{name: "Expired Beyond ClockSkew", now: time.UnixMilli(2011), clockSkew: 10 * time.Millisecond, wantErr: ErrDocumentExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validityInfo.Verify(tt.now, tt.clockSkew)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func Test_ValidityInfo_UpdateExpected(t *testing.T) {
+	expectedUpdate := time.UnixMilli(1500)
+
+	tests := []struct {
+		name           string
+		expectedUpdate *time.Time
+		now            time.Time
+		want           bool
+	}{
+		{name: "None", now: time.UnixMilli(1600)},
+		{name: "Before", expectedUpdate: &expectedUpdate, now: time.UnixMilli(1400)},
+		{name: "After", expectedUpdate: &expectedUpdate, now: time.UnixMilli(1600), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validityInfo := ValidityInfo{ExpectedUpdate: tt.expectedUpdate}
+			if got := validityInfo.UpdateExpected(tt.now); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_MobileSecurityObject_Verify(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		wantErr error
+	}{
+		{name: "Supported", version: MobileSecurityObjectVersion},
+		{name: "Unsupported", version: "2.0", wantErr: ErrUnsupportedMSOVersion},
+		{name: "Missing", version: "", wantErr: ErrUnsupportedMSOVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mobileSecurityObject := MobileSecurityObject{Version: tt.version}
+			if err := mobileSecurityObject.Verify(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func Test_ValidityInfo_MarshalCBOR(t *testing.T) {
+	location := time.FixedZone("+10", 10*60*60)
+	validityInfo := &ValidityInfo{
+		Signed:     time.Date(2020, time.October, 1, 23, 30, 2, 500_000_000, location),
+		ValidFrom:  time.Date(2020, time.October, 1, 23, 30, 2, 0, location),
+		ValidUntil: time.Date(2021, time.October, 1, 23, 30, 2, 0, location),
+	}
+
+	data, err := cbor2.Marshal(validityInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]cbor.RawTag
+	if err = cbor2.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"signed":     "2020-10-01T13:30:02Z",
+		"validFrom":  "2020-10-01T13:30:02Z",
+		"validUntil": "2021-10-01T13:30:02Z",
+	}
+	for key, value := range want {
+		tag, ok := got[key]
+		if !ok {
+			t.Fatalf("missing %s", key)
+		}
+		if tag.Number != cbor2.TagTdate {
+			t.Fatalf("%s: expected tag %d, got %d", key, cbor2.TagTdate, tag.Number)
+		}
+		var s string
+		if err = cbor2.Unmarshal(tag.Content, &s); err != nil {
+			t.Fatal(err)
+		}
+		if s != value {
+			t.Fatalf("%s: expected %s, got %s", key, value, s)
+		}
+	}
+}
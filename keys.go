@@ -2,7 +2,9 @@ package mdoc
 
 import (
 	"io"
+	"math"
 
+	cbor2 "github.com/alex-richards/go-mdoc/internal/cbor"
 	"github.com/veraison/go-cose"
 )
 
@@ -22,12 +24,199 @@ type Agreer interface {
 	Agree(publicKey *PublicKey) ([]byte, error)
 }
 
+// COSE curve identifiers of the Brainpool curves, which are not defined by go-cose.
+const (
+	COSECurveBrainpoolP256r1 cose.Curve = 256
+	COSECurveBrainpoolP320r1 cose.Curve = 257
+	COSECurveBrainpoolP384r1 cose.Curve = 258
+	COSECurveBrainpoolP512r1 cose.Curve = 259
+)
+
 type PublicKey cose.Key
 
 func (p *PublicKey) MarshalCBOR() ([]byte, error) {
 	return (*cose.Key)(p).MarshalCBOR()
 }
 
+// COSE key common parameter labels, RFC 9052 7.1, which go-cose does not export.
+const (
+	coseKeyLabelType      int64 = 1
+	coseKeyLabelID        int64 = 2
+	coseKeyLabelAlgorithm int64 = 3
+	coseKeyLabelOps       int64 = 4
+	coseKeyLabelBaseIV    int64 = 5
+)
+
+// UnmarshalCBOR decodes a COSE key. go-cose rejects OKP keys which are not 32 bytes, so
+// X448 and Ed448 keys are decoded by unmarshalOKP448 instead.
 func (p *PublicKey) UnmarshalCBOR(data []byte) error {
-	return (*cose.Key)(p).UnmarshalCBOR(data)
+	err := (*cose.Key)(p).UnmarshalCBOR(data)
+	if err == nil {
+		return nil
+	}
+
+	key, ok, okpErr := unmarshalOKP448(data)
+	if !ok {
+		return err
+	}
+	if okpErr != nil {
+		return okpErr
+	}
+
+	*p = *key
+	return nil
+}
+
+// unmarshalOKP448 decodes an X448 or Ed448 COSE key as go-cose decodes other keys, with
+// integers as int64 and parameters other than the common ones kept in Params. ok is false
+// if the data is not such a key.
+func unmarshalOKP448(data []byte) (key *PublicKey, ok bool, err error) {
+	var labels map[any]any
+	if err = cbor2.Unmarshal(data, &labels); err != nil {
+		return nil, false, nil
+	}
+
+	params := make(map[any]any, len(labels))
+	for label, value := range labels {
+		if label, isString := label.(string); isString {
+			params[label] = value
+			continue
+		}
+
+		intLabel, isInt := coseInt(label)
+		if !isInt {
+			return nil, false, nil
+		}
+		if intValue, isInt := coseInt(value); isInt {
+			value = intValue
+		}
+		params[intLabel] = value
+	}
+
+	keyType, _ := params[coseKeyLabelType].(int64)
+	curve, _ := params[cose.KeyLabelOKPCurve].(int64)
+	if cose.KeyType(keyType) != cose.KeyTypeOKP ||
+		cose.Curve(curve) != cose.CurveX448 && cose.Curve(curve) != cose.CurveEd448 {
+		return nil, false, nil
+	}
+
+	key = &PublicKey{Type: cose.KeyTypeOKP}
+
+	if id, exists := params[coseKeyLabelID]; exists {
+		if key.ID, ok = id.([]byte); !ok {
+			return nil, true, ErrInvalidPublicKey
+		}
+	}
+
+	if algorithm, exists := params[coseKeyLabelAlgorithm]; exists {
+		intAlgorithm, isInt := algorithm.(int64)
+		if !isInt {
+			return nil, true, ErrInvalidPublicKey
+		}
+		key.Algorithm = cose.Algorithm(intAlgorithm)
+	}
+
+	if ops, exists := params[coseKeyLabelOps]; exists {
+		opsArray, isArray := ops.([]any)
+		if !isArray {
+			return nil, true, ErrInvalidPublicKey
+		}
+		for _, op := range opsArray {
+			var keyOp cose.KeyOp
+			if stringOp, isString := op.(string); isString {
+				if keyOp, ok = cose.KeyOpFromString(stringOp); !ok {
+					return nil, true, ErrInvalidPublicKey
+				}
+			} else if intOp, isInt := coseInt(op); isInt {
+				keyOp = cose.KeyOp(intOp)
+			} else {
+				return nil, true, ErrInvalidPublicKey
+			}
+			key.Ops = append(key.Ops, keyOp)
+		}
+	}
+
+	if baseIV, exists := params[coseKeyLabelBaseIV]; exists {
+		if key.BaseIV, ok = baseIV.([]byte); !ok {
+			return nil, true, ErrInvalidPublicKey
+		}
+	}
+
+	for _, label := range []int64{coseKeyLabelType, coseKeyLabelID, coseKeyLabelAlgorithm, coseKeyLabelOps, coseKeyLabelBaseIV} {
+		delete(params, label)
+	}
+	params[cose.KeyLabelOKPCurve] = cose.Curve(curve)
+	key.Params = params
+
+	curveParams, err := key.curveParams()
+	if err != nil {
+		return nil, true, err
+	}
+
+	x, isBytes := params[cose.KeyLabelOKPX].([]byte)
+	if _, hasD := params[cose.KeyLabelOKPD]; !isBytes || len(x) != curveParams.Size || hasD {
+		return nil, true, ErrInvalidPublicKey
+	}
+	if key.Algorithm != cose.AlgorithmReserved && key.Algorithm != coseAlgorithm(curveParams.Curve) {
+		return nil, true, ErrInvalidPublicKey
+	}
+
+	return key, true, nil
+}
+
+// coseInt returns a CBOR integer decoded as int64 or uint64 as an int64.
+func coseInt(value any) (int64, bool) {
+	switch value := value.(type) {
+	case int64:
+		return value, true
+	case uint64:
+		return int64(value), value <= math.MaxInt64
+	default:
+		return 0, false
+	}
+}
+
+// Curve returns the Curve of the COSE key.
+func (p *PublicKey) Curve() (Curve, error) {
+	switch p.Type {
+	case cose.KeyTypeEC2:
+		switch p.Params[cose.KeyLabelEC2Curve] {
+		case cose.CurveP256:
+			return CurveP256, nil
+		case cose.CurveP384:
+			return CurveP384, nil
+		case cose.CurveP521:
+			return CurveP521, nil
+		case COSECurveBrainpoolP256r1:
+			return CurveBrainpoolP256r1, nil
+		case COSECurveBrainpoolP320r1:
+			return CurveBrainpoolP320r1, nil
+		case COSECurveBrainpoolP384r1:
+			return CurveBrainpoolP384r1, nil
+		case COSECurveBrainpoolP512r1:
+			return CurveBrainpoolP512r1, nil
+		}
+
+	case cose.KeyTypeOKP:
+		switch p.Params[cose.KeyLabelOKPCurve] {
+		case cose.CurveX25519:
+			return CurveX25519, nil
+		case cose.CurveX448:
+			return CurveX448, nil
+		case cose.CurveEd25519:
+			return CurveEd25519, nil
+		case cose.CurveEd448:
+			return CurveEd448, nil
+		}
+	}
+
+	return "", ErrUnsupportedCurve
+}
+
+func (p *PublicKey) verifier() (cose.Verifier, error) {
+	params, err := p.curveParams()
+	if err == nil && isBrainpool(params.EllipticCurve()) {
+		return newBrainpoolVerifier(p, params)
+	}
+	return (*cose.Key)(p).Verifier()
 }
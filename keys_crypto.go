@@ -0,0 +1,328 @@
+package mdoc
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+
+	"filippo.io/edwards25519"
+	"github.com/alex-richards/go-mdoc/internal/brainpool"
+	"github.com/cloudflare/circl/dh/x448"
+	"github.com/cloudflare/circl/ecc/goldilocks"
+	"github.com/cloudflare/circl/sign/ed448"
+	"github.com/veraison/go-cose"
+)
+
+var (
+	ErrInvalidPublicKey     = errors.New("mdoc: invalid public key")
+	ErrUnsupportedPublicKey = errors.New("mdoc: unsupported public key")
+)
+
+// CurveParams holds the identifiers of a curve in each of the supported key formats.
+type CurveParams struct {
+	Curve     Curve
+	KeyType   cose.KeyType
+	COSECurve cose.Curve
+	JWKCurve  string
+	OID       asn1.ObjectIdentifier
+	// Size is the length in bytes of an EC2 coordinate, or of an OKP public key.
+	Size int
+
+	ellipticCurve func() elliptic.Curve
+}
+
+var (
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+
+	curveParamsP256            = CurveParams{CurveP256, cose.KeyTypeEC2, cose.CurveP256, "P-256", asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}, 32, elliptic.P256}
+	curveParamsP384            = CurveParams{CurveP384, cose.KeyTypeEC2, cose.CurveP384, "P-384", asn1.ObjectIdentifier{1, 3, 132, 0, 34}, 48, elliptic.P384}
+	curveParamsP521            = CurveParams{CurveP521, cose.KeyTypeEC2, cose.CurveP521, "P-521", asn1.ObjectIdentifier{1, 3, 132, 0, 35}, 66, elliptic.P521}
+	curveParamsBrainpoolP256r1 = CurveParams{CurveBrainpoolP256r1, cose.KeyTypeEC2, COSECurveBrainpoolP256r1, "BP-256", asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 7}, 32, brainpool.P256r1}
+	curveParamsBrainpoolP320r1 = CurveParams{CurveBrainpoolP320r1, cose.KeyTypeEC2, COSECurveBrainpoolP320r1, "BP-320", asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 9}, 40, brainpool.P320r1}
+	curveParamsBrainpoolP384r1 = CurveParams{CurveBrainpoolP384r1, cose.KeyTypeEC2, COSECurveBrainpoolP384r1, "BP-384", asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 11}, 48, brainpool.P384r1}
+	curveParamsBrainpoolP512r1 = CurveParams{CurveBrainpoolP512r1, cose.KeyTypeEC2, COSECurveBrainpoolP512r1, "BP-512", asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 13}, 64, brainpool.P512r1}
+	curveParamsX25519          = CurveParams{CurveX25519, cose.KeyTypeOKP, cose.CurveX25519, "X25519", asn1.ObjectIdentifier{1, 3, 101, 110}, 32, nil}
+	curveParamsX448            = CurveParams{CurveX448, cose.KeyTypeOKP, cose.CurveX448, "X448", asn1.ObjectIdentifier{1, 3, 101, 111}, x448.Size, nil}
+	curveParamsEd25519         = CurveParams{CurveEd25519, cose.KeyTypeOKP, cose.CurveEd25519, "Ed25519", asn1.ObjectIdentifier{1, 3, 101, 112}, ed25519.PublicKeySize, nil}
+	curveParamsEd448           = CurveParams{CurveEd448, cose.KeyTypeOKP, cose.CurveEd448, "Ed448", asn1.ObjectIdentifier{1, 3, 101, 113}, ed448.PublicKeySize, nil}
+
+	allCurveParams = []*CurveParams{
+		&curveParamsP256,
+		&curveParamsP384,
+		&curveParamsP521,
+		&curveParamsBrainpoolP256r1,
+		&curveParamsBrainpoolP320r1,
+		&curveParamsBrainpoolP384r1,
+		&curveParamsBrainpoolP512r1,
+		&curveParamsX25519,
+		&curveParamsX448,
+		&curveParamsEd25519,
+		&curveParamsEd448,
+	}
+)
+
+// FindCurveParams returns a copy of the parameters of the first supported curve for which
+// match returns true, or ErrUnsupportedCurve.
+func FindCurveParams(match func(*CurveParams) bool) (*CurveParams, error) {
+	for _, params := range allCurveParams {
+		if match(params) {
+			found := *params
+			return &found, nil
+		}
+	}
+	return nil, ErrUnsupportedCurve
+}
+
+// Params returns the parameters of the curve.
+func (c Curve) Params() (*CurveParams, error) {
+	return FindCurveParams(func(params *CurveParams) bool {
+		return params.Curve == c
+	})
+}
+
+func (p *PublicKey) curveParams() (*CurveParams, error) {
+	curve, err := p.Curve()
+	if err != nil {
+		return nil, err
+	}
+	return curve.Params()
+}
+
+// EllipticCurve returns the elliptic.Curve of an EC2 curve, or nil for an OKP curve.
+func (cp *CurveParams) EllipticCurve() elliptic.Curve {
+	if cp.ellipticCurve == nil {
+		return nil
+	}
+	return cp.ellipticCurve()
+}
+
+// Algorithm returns the signature algorithm a key on the curve may carry. go-cose rejects
+// keys with an algorithm it cannot derive from the curve, so the Brainpool keys have none.
+func (cp *CurveParams) Algorithm() cose.Algorithm {
+	if isBrainpool(cp.EllipticCurve()) {
+		return cose.AlgorithmReserved
+	}
+	return coseAlgorithm(cp.Curve)
+}
+
+// DigestAlgorithm returns the digest of the ECDSA signature algorithm of an EC2 curve.
+func (cp *CurveParams) DigestAlgorithm() (DigestAlgorithm, error) {
+	if cp.KeyType != cose.KeyTypeEC2 {
+		return "", ErrUnsupportedCurve
+	}
+
+	switch coseAlgorithm(cp.Curve) {
+	case cose.AlgorithmES256:
+		return DigestAlgorithmSHA256, nil
+	case cose.AlgorithmES384:
+		return DigestAlgorithmSHA384, nil
+	case cose.AlgorithmES512:
+		return DigestAlgorithmSHA512, nil
+	default:
+		return "", ErrUnsupportedCurve
+	}
+}
+
+// NewPublicKey converts a public key to a COSE key. Supported keys are *ecdsa.PublicKey
+// on the NIST and Brainpool curves, *ecdh.PublicKey, ed25519.PublicKey, and the circl
+// ed448.PublicKey and x448.Key. ECDSA keys on the NIST curves carry their signature
+// algorithm, other keys leave it unset.
+func NewPublicKey(publicKey crypto.PublicKey) (*PublicKey, error) {
+	switch publicKey := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		params, err := FindCurveParams(func(params *CurveParams) bool {
+			return params.KeyType == cose.KeyTypeEC2 && params.EllipticCurve() == publicKey.Curve
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if publicKey.X == nil || publicKey.Y == nil ||
+			!publicKey.Curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return nil, ErrInvalidPublicKey
+		}
+
+		point := make([]byte, 1+2*params.Size)
+		point[0] = 4
+		publicKey.X.FillBytes(point[1 : 1+params.Size])
+		publicKey.Y.FillBytes(point[1+params.Size:])
+
+		key, err := newEC2PublicKey(params, point)
+		if err != nil {
+			return nil, err
+		}
+		key.Algorithm = params.Algorithm()
+		return key, nil
+
+	case *ecdh.PublicKey:
+		switch publicKey.Curve() {
+		case ecdh.P256():
+			return newEC2PublicKey(&curveParamsP256, publicKey.Bytes())
+		case ecdh.P384():
+			return newEC2PublicKey(&curveParamsP384, publicKey.Bytes())
+		case ecdh.P521():
+			return newEC2PublicKey(&curveParamsP521, publicKey.Bytes())
+		case ecdh.X25519():
+			return newOKPPublicKey(&curveParamsX25519, publicKey.Bytes())
+		default:
+			return nil, ErrUnsupportedCurve
+		}
+
+	case ed25519.PublicKey:
+		return newOKPPublicKey(&curveParamsEd25519, publicKey)
+	case ed448.PublicKey:
+		return newOKPPublicKey(&curveParamsEd448, publicKey)
+	case x448.Key:
+		return newOKPPublicKey(&curveParamsX448, publicKey[:])
+	case *x448.Key:
+		return newOKPPublicKey(&curveParamsX448, publicKey[:])
+
+	default:
+		return nil, ErrUnsupportedPublicKey
+	}
+}
+
+// newEC2PublicKey creates a COSE key from an uncompressed point.
+func newEC2PublicKey(params *CurveParams, point []byte) (*PublicKey, error) {
+	if len(point) != 1+2*params.Size || point[0] != 4 {
+		return nil, ErrInvalidPublicKey
+	}
+
+	x := make([]byte, params.Size)
+	copy(x, point[1:1+params.Size])
+
+	y := make([]byte, params.Size)
+	copy(y, point[1+params.Size:])
+
+	return &PublicKey{
+		Type: cose.KeyTypeEC2,
+		Params: map[any]any{
+			cose.KeyLabelEC2Curve: params.COSECurve,
+			cose.KeyLabelEC2X:     x,
+			cose.KeyLabelEC2Y:     y,
+		},
+	}, nil
+}
+
+func newOKPPublicKey(params *CurveParams, publicKey []byte) (*PublicKey, error) {
+	if len(publicKey) != params.Size || !isValidOKPPoint(params, publicKey) {
+		return nil, ErrInvalidPublicKey
+	}
+
+	x := make([]byte, params.Size)
+	copy(x, publicKey)
+
+	return &PublicKey{
+		Type: cose.KeyTypeOKP,
+		Params: map[any]any{
+			cose.KeyLabelOKPCurve: params.COSECurve,
+			cose.KeyLabelOKPX:     x,
+		},
+	}, nil
+}
+
+// CryptoPublicKey converts a COSE key to a public key, checking EC2 points are on the
+// curve. EC2 keys are returned as *ecdsa.PublicKey, X25519 keys as *ecdh.PublicKey, X448
+// keys as *x448.Key, and Ed25519 and Ed448 keys as ed25519.PublicKey and ed448.PublicKey.
+func (p *PublicKey) CryptoPublicKey() (crypto.PublicKey, error) {
+	params, err := p.curveParams()
+	if err != nil {
+		return nil, err
+	}
+
+	switch params.KeyType {
+	case cose.KeyTypeEC2:
+		point, err := p.uncompressedPoint(params)
+		if err != nil {
+			return nil, err
+		}
+
+		publicKey := &ecdsa.PublicKey{
+			Curve: params.EllipticCurve(),
+			X:     new(big.Int).SetBytes(point[1 : 1+params.Size]),
+			Y:     new(big.Int).SetBytes(point[1+params.Size:]),
+		}
+		if !publicKey.Curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return nil, ErrInvalidPublicKey
+		}
+		return publicKey, nil
+
+	default: // cose.KeyTypeOKP
+		x, ok := p.Params[cose.KeyLabelOKPX].([]byte)
+		if !ok || len(x) != params.Size {
+			return nil, ErrInvalidPublicKey
+		}
+
+		switch params.Curve {
+		case CurveX25519:
+			return ecdh.X25519().NewPublicKey(x)
+		case CurveX448:
+			publicKey := new(x448.Key)
+			copy(publicKey[:], x)
+			return publicKey, nil
+		case CurveEd25519:
+			if !isValidOKPPoint(params, x) {
+				return nil, ErrInvalidPublicKey
+			}
+			return ed25519.PublicKey(append([]byte{}, x...)), nil
+		default: // CurveEd448
+			if !isValidOKPPoint(params, x) {
+				return nil, ErrInvalidPublicKey
+			}
+			return ed448.PublicKey(append([]byte{}, x...)), nil
+		}
+	}
+}
+
+// isValidOKPPoint reports whether x decodes to a point on the curve of an Ed25519 or Ed448
+// key. The X25519 and X448 functions accept any input, so those keys are always valid.
+func isValidOKPPoint(params *CurveParams, x []byte) bool {
+	switch params.Curve {
+	case CurveEd25519:
+		_, err := new(edwards25519.Point).SetBytes(x)
+		return err == nil
+	case CurveEd448:
+		_, err := goldilocks.FromBytes(x)
+		return err == nil
+	default:
+		return true
+	}
+}
+
+// UncompressedPoint returns the coordinates of an EC2 key as an uncompressed point. Short
+// coordinates, as written by some encoders, are padded with leading zeros.
+func (p *PublicKey) UncompressedPoint() ([]byte, error) {
+	params, err := p.curveParams()
+	if err != nil {
+		return nil, err
+	}
+	if params.KeyType != cose.KeyTypeEC2 {
+		return nil, ErrUnsupportedPublicKey
+	}
+	return p.uncompressedPoint(params)
+}
+
+// uncompressedPoint returns the coordinates of an EC2 key as an uncompressed point,
+// restoring leading zeros omitted by some encoders.
+func (p *PublicKey) uncompressedPoint(params *CurveParams) ([]byte, error) {
+	x, ok := p.Params[cose.KeyLabelEC2X].([]byte)
+	if !ok || len(x) == 0 || len(x) > params.Size {
+		return nil, ErrInvalidPublicKey
+	}
+
+	y, ok := p.Params[cose.KeyLabelEC2Y].([]byte)
+	if !ok || len(y) == 0 || len(y) > params.Size {
+		return nil, ErrInvalidPublicKey
+	}
+
+	point := make([]byte, 1+2*params.Size)
+	point[0] = 4
+	copy(point[1+params.Size-len(x):], x)
+	copy(point[1+2*params.Size-len(y):], y)
+	return point, nil
+}
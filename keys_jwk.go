@@ -0,0 +1,143 @@
+package mdoc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/veraison/go-cose"
+)
+
+const (
+	jwkKeyTypeEC  = "EC"
+	jwkKeyTypeOKP = "OKP"
+)
+
+// jwk is a public JSON Web Key, RFC 7517, with the EC and OKP parameters of RFC 7518 and
+// RFC 8037. The Brainpool curves, which have no registered names, use BP-256, BP-320,
+// BP-384 and BP-512.
+type jwk struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+
+	// D is the private key, which is only decoded so that private JWKs can be rejected.
+	D *string `json:"d,omitempty"`
+}
+
+var jwkAlgorithms = map[cose.Algorithm]string{
+	cose.AlgorithmES256: "ES256",
+	cose.AlgorithmES384: "ES384",
+	cose.AlgorithmES512: "ES512",
+	cose.AlgorithmEdDSA: "EdDSA",
+}
+
+// MarshalJWK encodes the key as a JSON Web Key.
+func (p *PublicKey) MarshalJWK() ([]byte, error) {
+	if _, err := p.CryptoPublicKey(); err != nil {
+		return nil, err
+	}
+
+	params, err := p.curveParams()
+	if err != nil {
+		return nil, err
+	}
+
+	key := jwk{
+		Curve: params.JWKCurve,
+	}
+
+	if p.Algorithm != cose.AlgorithmReserved {
+		algorithm, ok := jwkAlgorithms[p.Algorithm]
+		if !ok {
+			return nil, ErrUnsupportedPublicKey
+		}
+		key.Algorithm = algorithm
+	}
+
+	if params.KeyType == cose.KeyTypeEC2 {
+		point, err := p.uncompressedPoint(params)
+		if err != nil {
+			return nil, err
+		}
+
+		key.KeyType = jwkKeyTypeEC
+		key.X = base64.RawURLEncoding.EncodeToString(point[1 : 1+params.Size])
+		key.Y = base64.RawURLEncoding.EncodeToString(point[1+params.Size:])
+	} else {
+		key.KeyType = jwkKeyTypeOKP
+		key.X = base64.RawURLEncoding.EncodeToString(p.Params[cose.KeyLabelOKPX].([]byte))
+	}
+
+	return json.Marshal(key)
+}
+
+// PublicKeyFromJWK decodes a JSON Web Key, checking the key is valid for its curve. Keys
+// carrying the private key member d are rejected.
+func PublicKeyFromJWK(data []byte) (*PublicKey, error) {
+	var key jwk
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, err
+	}
+	if key.D != nil {
+		return nil, ErrInvalidPublicKey
+	}
+
+	var keyType cose.KeyType
+	switch key.KeyType {
+	case jwkKeyTypeEC:
+		keyType = cose.KeyTypeEC2
+	case jwkKeyTypeOKP:
+		keyType = cose.KeyTypeOKP
+	default:
+		return nil, ErrUnsupportedPublicKey
+	}
+
+	params, err := FindCurveParams(func(params *CurveParams) bool {
+		return params.KeyType == keyType && params.JWKCurve == key.Curve
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(key.X)
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+
+	var publicKey *PublicKey
+	if keyType == cose.KeyTypeEC2 {
+		y, err := base64.RawURLEncoding.DecodeString(key.Y)
+		if err != nil || len(x) != params.Size || len(y) != params.Size {
+			return nil, ErrInvalidPublicKey
+		}
+
+		publicKey, err = newEC2PublicKey(params, append(append([]byte{4}, x...), y...))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if key.Y != "" {
+			return nil, ErrInvalidPublicKey
+		}
+
+		publicKey, err = newOKPPublicKey(params, x)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if key.Algorithm != "" {
+		algorithm := params.Algorithm()
+		if algorithm == cose.AlgorithmReserved || key.Algorithm != jwkAlgorithms[algorithm] {
+			return nil, ErrUnsupportedPublicKey
+		}
+		publicKey.Algorithm = algorithm
+	}
+
+	if _, err = publicKey.CryptoPublicKey(); err != nil {
+		return nil, err
+	}
+	return publicKey, nil
+}
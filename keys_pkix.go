@@ -0,0 +1,140 @@
+package mdoc
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+
+	"github.com/veraison/go-cose"
+)
+
+const pemTypePublicKey = "PUBLIC KEY"
+
+// subjectPublicKeyInfo is the X.509 SubjectPublicKeyInfo, RFC 5280. crypto/x509 does not
+// support the Brainpool, X448 or Ed448 keys, so it is encoded here for every curve.
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// MarshalPKIX encodes the key as a DER SubjectPublicKeyInfo. EC2 keys are encoded as
+// uncompressed points on a named curve, RFC 5480, and OKP keys as in RFC 8410.
+func (p *PublicKey) MarshalPKIX() ([]byte, error) {
+	if _, err := p.CryptoPublicKey(); err != nil {
+		return nil, err
+	}
+
+	params, err := p.curveParams()
+	if err != nil {
+		return nil, err
+	}
+
+	var info subjectPublicKeyInfo
+	if params.KeyType == cose.KeyTypeEC2 {
+		namedCurve, err := asn1.Marshal(params.OID)
+		if err != nil {
+			return nil, err
+		}
+
+		point, err := p.uncompressedPoint(params)
+		if err != nil {
+			return nil, err
+		}
+
+		info.Algorithm = pkix.AlgorithmIdentifier{
+			Algorithm:  oidPublicKeyECDSA,
+			Parameters: asn1.RawValue{FullBytes: namedCurve},
+		}
+		info.PublicKey = asn1.BitString{Bytes: point, BitLength: 8 * len(point)}
+	} else {
+		x := p.Params[cose.KeyLabelOKPX].([]byte)
+
+		info.Algorithm = pkix.AlgorithmIdentifier{
+			Algorithm: params.OID,
+		}
+		info.PublicKey = asn1.BitString{Bytes: x, BitLength: 8 * len(x)}
+	}
+
+	return asn1.Marshal(info)
+}
+
+// PublicKeyFromPKIX decodes a DER SubjectPublicKeyInfo, checking the key is valid for its
+// curve. ECDSA keys on the NIST curves carry their signature algorithm, as for
+// NewPublicKey.
+func PublicKeyFromPKIX(der []byte) (*PublicKey, error) {
+	var info subjectPublicKeyInfo
+	rest, err := asn1.Unmarshal(der, &info)
+	if err != nil || len(rest) != 0 || info.PublicKey.BitLength != 8*len(info.PublicKey.Bytes) {
+		return nil, ErrInvalidPublicKey
+	}
+
+	var publicKey *PublicKey
+	if info.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		var namedCurve asn1.ObjectIdentifier
+		rest, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &namedCurve)
+		if err != nil || len(rest) != 0 {
+			return nil, ErrUnsupportedCurve
+		}
+
+		params, err := FindCurveParams(func(params *CurveParams) bool {
+			return params.KeyType == cose.KeyTypeEC2 && params.OID.Equal(namedCurve)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		publicKey, err = newEC2PublicKey(params, info.PublicKey.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		publicKey.Algorithm = params.Algorithm()
+	} else {
+		params, err := FindCurveParams(func(params *CurveParams) bool {
+			return params.KeyType == cose.KeyTypeOKP && params.OID.Equal(info.Algorithm.Algorithm)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(info.Algorithm.Parameters.FullBytes) != 0 {
+			return nil, ErrInvalidPublicKey
+		}
+
+		publicKey, err = newOKPPublicKey(params, info.PublicKey.Bytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err = publicKey.CryptoPublicKey(); err != nil {
+		return nil, err
+	}
+	return publicKey, nil
+}
+
+// MarshalPEM encodes the key as a PEM "PUBLIC KEY" block.
+func (p *PublicKey) MarshalPEM() ([]byte, error) {
+	der, err := p.MarshalPKIX()
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  pemTypePublicKey,
+		Bytes: der,
+	}), nil
+}
+
+// PublicKeyFromPEM decodes the first PEM "PUBLIC KEY" block in data.
+func PublicKeyFromPEM(data []byte) (*PublicKey, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, ErrInvalidPublicKey
+		}
+
+		if block.Type == pemTypePublicKey {
+			return PublicKeyFromPKIX(block.Bytes)
+		}
+	}
+}
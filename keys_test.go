@@ -0,0 +1,479 @@
+package mdoc
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/x509"
+	"errors"
+	"math/big"
+	"testing"
+
+	cbor2 "github.com/alex-richards/go-mdoc/internal/cbor"
+	"github.com/alex-richards/go-mdoc/internal/testutil"
+	"github.com/cloudflare/circl/dh/x448"
+	"github.com/cloudflare/circl/sign/ed448"
+	"github.com/google/go-cmp/cmp"
+	"github.com/veraison/go-cose"
+)
+
+func newTestPublicKeys(t *testing.T) map[Curve]crypto.PublicKey {
+	t.Helper()
+	rand := testutil.NewDeterministicRand(t)
+
+	publicKeys := make(map[Curve]crypto.PublicKey)
+
+	for _, curve := range []Curve{
+		CurveP256, CurveP384, CurveP521,
+		CurveBrainpoolP256r1, CurveBrainpoolP320r1, CurveBrainpoolP384r1, CurveBrainpoolP512r1,
+	} {
+		params, err := curve.Params()
+		if err != nil {
+			t.Fatal(err)
+		}
+		privateKey, err := ecdsa.GenerateKey(params.EllipticCurve(), rand)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKeys[curve] = &privateKey.PublicKey
+	}
+
+	x25519PrivateKey, err := ecdh.X25519().GenerateKey(rand)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKeys[CurveX25519] = x25519PrivateKey.PublicKey()
+
+	var x448PrivateKey, x448PublicKey x448.Key
+	if _, err = rand.Read(x448PrivateKey[:]); err != nil {
+		t.Fatal(err)
+	}
+	x448.KeyGen(&x448PublicKey, &x448PrivateKey)
+	publicKeys[CurveX448] = &x448PublicKey
+
+	ed25519PublicKey, _, err := ed25519.GenerateKey(rand)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKeys[CurveEd25519] = ed25519PublicKey
+
+	ed448PublicKey, _, err := ed448.GenerateKey(rand)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKeys[CurveEd448] = ed448PublicKey
+
+	return publicKeys
+}
+
+func Test_PublicKey_RoundTrip(t *testing.T) {
+	for curve, cryptoPublicKey := range newTestPublicKeys(t) {
+		t.Run(curve.Name(), func(t *testing.T) {
+			publicKey, err := NewPublicKey(cryptoPublicKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gotCurve, err := publicKey.Curve()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotCurve != curve {
+				t.Fatalf("expected %s, got %s", curve, gotCurve)
+			}
+
+			t.Run("Crypto", func(t *testing.T) {
+				got, err := publicKey.CryptoPublicKey()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var equal bool
+				switch want := cryptoPublicKey.(type) {
+				case *ecdsa.PublicKey:
+					got, ok := got.(*ecdsa.PublicKey)
+					equal = ok && want.Curve == got.Curve && want.X.Cmp(got.X) == 0 && want.Y.Cmp(got.Y) == 0
+				case *x448.Key:
+					got, ok := got.(*x448.Key)
+					equal = ok && *want == *got
+				case interface{ Equal(crypto.PublicKey) bool }:
+					equal = want.Equal(got)
+				}
+				if !equal {
+					t.Fatalf("expected %v, got %v", cryptoPublicKey, got)
+				}
+			})
+
+			t.Run("CBOR", func(t *testing.T) {
+				data, err := cbor2.Marshal(publicKey)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got := new(PublicKey)
+				if err = cbor2.Unmarshal(data, got); err != nil {
+					t.Fatal(err)
+				}
+
+				if diff := cmp.Diff(publicKey, got); diff != "" {
+					t.Fatal(diff)
+				}
+			})
+
+			t.Run("JWK", func(t *testing.T) {
+				data, err := publicKey.MarshalJWK()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got, err := PublicKeyFromJWK(data)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if diff := cmp.Diff(publicKey, got); diff != "" {
+					t.Fatal(diff)
+				}
+			})
+
+			t.Run("PEM", func(t *testing.T) {
+				data, err := publicKey.MarshalPEM()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got, err := PublicKeyFromPEM(data)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if diff := cmp.Diff(publicKey, got); diff != "" {
+					t.Fatal(diff)
+				}
+			})
+		})
+	}
+}
+
+func Test_PublicKey_UnmarshalCBOR_Labels(t *testing.T) {
+	publicKeys := newTestPublicKeys(t)
+
+	for _, curve := range []Curve{CurveP256, CurveEd25519, CurveX448, CurveEd448} {
+		t.Run(curve.Name(), func(t *testing.T) {
+			publicKey, err := NewPublicKey(publicKeys[curve])
+			if err != nil {
+				t.Fatal(err)
+			}
+			publicKey.ID = []byte{1, 2, 3, 4}
+			publicKey.Ops = []cose.KeyOp{cose.KeyOpVerify}
+			publicKey.Params[int64(-99)] = int64(5)
+			publicKey.Params["lorem"] = "ipsum"
+
+			data, err := cbor2.Marshal(publicKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := new(PublicKey)
+			if err = cbor2.Unmarshal(data, got); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(publicKey, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+
+	ed448PublicKey := []byte(publicKeys[CurveEd448].(ed448.PublicKey))
+
+	tests := []struct {
+		name string
+		key  map[any]any
+	}{
+		{
+			name: "Short X",
+			key:  map[any]any{int64(1): cose.KeyTypeOKP, int64(-1): cose.CurveX448, int64(-2): make([]byte, x448.Size-1)},
+		},
+		{
+			name: "Private",
+			key:  map[any]any{int64(1): cose.KeyTypeOKP, int64(-1): cose.CurveEd448, int64(-2): ed448PublicKey, int64(-4): ed448PublicKey},
+		},
+		{
+			name: "Algorithm",
+			key:  map[any]any{int64(1): cose.KeyTypeOKP, int64(3): cose.AlgorithmES256, int64(-1): cose.CurveEd448, int64(-2): ed448PublicKey},
+		},
+		{
+			name: "ID",
+			key:  map[any]any{int64(1): cose.KeyTypeOKP, int64(2): int64(1), int64(-1): cose.CurveEd448, int64(-2): ed448PublicKey},
+		},
+		{
+			name: "Key Ops",
+			key:  map[any]any{int64(1): cose.KeyTypeOKP, int64(4): []any{"lorem"}, int64(-1): cose.CurveEd448, int64(-2): ed448PublicKey},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := cbor2.Marshal(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err = cbor2.Unmarshal(data, new(PublicKey)); !errors.Is(err, ErrInvalidPublicKey) {
+				t.Fatalf("expected %v, got %v", ErrInvalidPublicKey, err)
+			}
+		})
+	}
+}
+
+func Test_PublicKey_MarshalPKIX_X509(t *testing.T) {
+	publicKeys := newTestPublicKeys(t)
+
+	for _, curve := range []Curve{CurveP256, CurveP384, CurveP521, CurveX25519, CurveEd25519} {
+		t.Run(curve.Name(), func(t *testing.T) {
+			publicKey, err := NewPublicKey(publicKeys[curve])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			der, err := publicKey.MarshalPKIX()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want, err := x509.MarshalPKIXPublicKey(publicKeys[curve])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(want, der) {
+				t.Fatalf("expected %x, got %x", want, der)
+			}
+
+			got, err := PublicKeyFromPKIX(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(publicKey, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_PublicKeyFromJWK(t *testing.T) {
+	tests := []struct {
+		name    string
+		jwk     string
+		want    *PublicKey
+		wantErr error
+	}{
+		{
+			// RFC 8037 Appendix A.2
+			name: "Ed25519",
+			jwk:  `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`,
+			want: &PublicKey{
+				Type: cose.KeyTypeOKP,
+				Params: map[any]any{
+					cose.KeyLabelOKPCurve: cose.CurveEd25519,
+					cose.KeyLabelOKPX:     testutil.DecodeHex(t, "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"),
+				},
+			},
+		},
+		{
+			name: "Algorithm",
+			jwk:  `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo","alg":"EdDSA"}`,
+			want: &PublicKey{
+				Type:      cose.KeyTypeOKP,
+				Algorithm: cose.AlgorithmEdDSA,
+				Params: map[any]any{
+					cose.KeyLabelOKPCurve: cose.CurveEd25519,
+					cose.KeyLabelOKPX:     testutil.DecodeHex(t, "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"),
+				},
+			},
+		},
+		{
+			// RFC 8037 Appendix A.1
+			name:    "Private Key",
+			jwk:     `{"kty":"OKP","crv":"Ed25519","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`,
+			wantErr: ErrInvalidPublicKey,
+		},
+		{
+			name:    "Invalid Ed25519",
+			jwk:     `{"kty":"OKP","crv":"Ed25519","x":"AgAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"}`,
+			wantErr: ErrInvalidPublicKey,
+		},
+		{
+			name:    "Mismatched Algorithm",
+			jwk:     `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo","alg":"ES256"}`,
+			wantErr: ErrUnsupportedPublicKey,
+		},
+		{
+			name:    "Unsupported Curve",
+			jwk:     `{"kty":"EC","crv":"secp256k1","x":"AA","y":"AA"}`,
+			wantErr: ErrUnsupportedCurve,
+		},
+		{
+			name:    "Unsupported Key Type",
+			jwk:     `{"kty":"RSA","n":"AQAB","e":"AQAB"}`,
+			wantErr: ErrUnsupportedPublicKey,
+		},
+		{
+			name:    "Short Coordinate",
+			jwk:     `{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}`,
+			wantErr: ErrInvalidPublicKey,
+		},
+		{
+			name:    "Not On Curve",
+			jwk:     `{"kty":"EC","crv":"P-256","x":"AQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA","y":"AQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"}`,
+			wantErr: ErrInvalidPublicKey,
+		},
+		{
+			name:    "Not On Brainpool Curve",
+			jwk:     `{"kty":"EC","crv":"BP-256","x":"AQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA","y":"AQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"}`,
+			wantErr: ErrInvalidPublicKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PublicKeyFromJWK([]byte(tt.jwk))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+// invalidEd25519 encodes y = 2, for which there is no x on the curve.
+var invalidEd25519 = append([]byte{2}, make([]byte, ed25519.PublicKeySize-1)...)
+
+func Test_PublicKey_CryptoPublicKey_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		publicKey *PublicKey
+		wantErr   error
+	}{
+		{
+			name: "Not On Curve",
+			publicKey: &PublicKey{
+				Type: cose.KeyTypeEC2,
+				Params: map[any]any{
+					cose.KeyLabelEC2Curve: cose.CurveP256,
+					cose.KeyLabelEC2X:     []byte{1},
+					cose.KeyLabelEC2Y:     []byte{1},
+				},
+			},
+			wantErr: ErrInvalidPublicKey,
+		},
+		{
+			name: "Compressed Point",
+			publicKey: &PublicKey{
+				Type: cose.KeyTypeEC2,
+				Params: map[any]any{
+					cose.KeyLabelEC2Curve: cose.CurveP256,
+					cose.KeyLabelEC2X:     []byte{1},
+					cose.KeyLabelEC2Y:     true,
+				},
+			},
+			wantErr: ErrInvalidPublicKey,
+		},
+		{
+			name: "Short OKP",
+			publicKey: &PublicKey{
+				Type: cose.KeyTypeOKP,
+				Params: map[any]any{
+					cose.KeyLabelOKPCurve: cose.CurveX448,
+					cose.KeyLabelOKPX:     make([]byte, 32),
+				},
+			},
+			wantErr: ErrInvalidPublicKey,
+		},
+		{
+			name: "Invalid Ed25519",
+			publicKey: &PublicKey{
+				Type: cose.KeyTypeOKP,
+				Params: map[any]any{
+					cose.KeyLabelOKPCurve: cose.CurveEd25519,
+					cose.KeyLabelOKPX:     invalidEd25519,
+				},
+			},
+			wantErr: ErrInvalidPublicKey,
+		},
+		{
+			name: "Invalid Ed448",
+			publicKey: &PublicKey{
+				Type: cose.KeyTypeOKP,
+				Params: map[any]any{
+					cose.KeyLabelOKPCurve: cose.CurveEd448,
+					cose.KeyLabelOKPX:     bytes.Repeat([]byte{0xff}, ed448.PublicKeySize),
+				},
+			},
+			wantErr: ErrInvalidPublicKey,
+		},
+		{
+			name: "Unsupported Curve",
+			publicKey: &PublicKey{
+				Type: cose.KeyTypeEC2,
+				Params: map[any]any{
+					cose.KeyLabelEC2Curve: cose.Curve(8),
+				},
+			},
+			wantErr: ErrUnsupportedCurve,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.publicKey.CryptoPublicKey(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if _, err := tt.publicKey.MarshalPKIX(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func Test_NewPublicKey_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		publicKey crypto.PublicKey
+		wantErr   error
+	}{
+		{
+			name:      "Not On Curve",
+			publicKey: &ecdsa.PublicKey{Curve: elliptic.P256(), X: big.NewInt(1), Y: big.NewInt(1)},
+			wantErr:   ErrInvalidPublicKey,
+		},
+		{
+			name:      "Short Ed25519",
+			publicKey: ed25519.PublicKey{1, 2, 3},
+			wantErr:   ErrInvalidPublicKey,
+		},
+		{
+			name:      "Invalid Ed25519",
+			publicKey: ed25519.PublicKey(invalidEd25519),
+			wantErr:   ErrInvalidPublicKey,
+		},
+		{
+			name:      "Unsupported",
+			publicKey: "key",
+			wantErr:   ErrUnsupportedPublicKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPublicKey(tt.publicKey); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package mdl
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alex-richards/go-mdoc"
+	mdoccbor "github.com/alex-richards/go-mdoc/internal/cbor"
+)
+
+var (
+	ErrMissingNameSpace = errors.New("mdoc: mdl: missing name space")
+	ErrInvalidAgeOver   = errors.New("mdoc: mdl: age over must be between 0 and 99")
+)
+
+const (
+	DocType   mdoc.DocType   = "org.iso.18013.5.1.mDL"
+	NameSpace mdoc.NameSpace = "org.iso.18013.5.1"
+)
+
+const (
+	FamilyName                  mdoc.DataElementIdentifier = "family_name"
+	GivenName                   mdoc.DataElementIdentifier = "given_name"
+	BirthDate                   mdoc.DataElementIdentifier = "birth_date"
+	IssueDate                   mdoc.DataElementIdentifier = "issue_date"
+	ExpiryDate                  mdoc.DataElementIdentifier = "expiry_date"
+	IssuingCountry              mdoc.DataElementIdentifier = "issuing_country"
+	IssuingAuthority            mdoc.DataElementIdentifier = "issuing_authority"
+	DocumentNumber              mdoc.DataElementIdentifier = "document_number"
+	Portrait                    mdoc.DataElementIdentifier = "portrait"
+	DrivingPrivileges           mdoc.DataElementIdentifier = "driving_privileges"
+	UNDistinguishingSign        mdoc.DataElementIdentifier = "un_distinguishing_sign"
+	AdministrativeNumber        mdoc.DataElementIdentifier = "administrative_number"
+	Sex                         mdoc.DataElementIdentifier = "sex"
+	Height                      mdoc.DataElementIdentifier = "height"
+	Weight                      mdoc.DataElementIdentifier = "weight"
+	EyeColour                   mdoc.DataElementIdentifier = "eye_colour"
+	HairColour                  mdoc.DataElementIdentifier = "hair_colour"
+	BirthPlace                  mdoc.DataElementIdentifier = "birth_place"
+	ResidentAddress             mdoc.DataElementIdentifier = "resident_address"
+	PortraitCaptureDate         mdoc.DataElementIdentifier = "portrait_capture_date"
+	AgeInYears                  mdoc.DataElementIdentifier = "age_in_years"
+	AgeBirthYear                mdoc.DataElementIdentifier = "age_birth_year"
+	IssuingJurisdiction         mdoc.DataElementIdentifier = "issuing_jurisdiction"
+	Nationality                 mdoc.DataElementIdentifier = "nationality"
+	ResidentCity                mdoc.DataElementIdentifier = "resident_city"
+	ResidentState               mdoc.DataElementIdentifier = "resident_state"
+	ResidentPostalCode          mdoc.DataElementIdentifier = "resident_postal_code"
+	ResidentCountry             mdoc.DataElementIdentifier = "resident_country"
+	FamilyNameNationalCharacter mdoc.DataElementIdentifier = "family_name_national_character"
+	GivenNameNationalCharacter  mdoc.DataElementIdentifier = "given_name_national_character"
+	SignatureUsualMark          mdoc.DataElementIdentifier = "signature_usual_mark"
+
+	ageOverPrefix = "age_over_"
+)
+
+// AgeOver returns the age_over_NN data element identifier for age, which must have two
+// digits.
+func AgeOver(age uint) (mdoc.DataElementIdentifier, error) {
+	if age > 99 {
+		return "", ErrInvalidAgeOver
+	}
+	return mdoc.DataElementIdentifier(fmt.Sprintf("%s%02d", ageOverPrefix, age)), nil
+}
+
+// MDL holds the data elements of the org.iso.18013.5.1 name space.
+// Nil values are absent, so that zero and empty values such as sex 0, "not known", are
+// kept. AgeOver holds only the age_over_NN elements present.
+type MDL struct {
+	FamilyName           *string
+	GivenName            *string
+	BirthDate            *mdoc.FullDate
+	IssueDate            *mdoc.FullDate
+	ExpiryDate           *mdoc.FullDate
+	IssuingCountry       *string
+	IssuingAuthority     *string
+	DocumentNumber       *string
+	Portrait             []byte
+	DrivingPrivileges    []DrivingPrivilege
+	UNDistinguishingSign *string
+
+	AdministrativeNumber        *string
+	Sex                         *uint
+	Height                      *uint
+	Weight                      *uint
+	EyeColour                   *string
+	HairColour                  *string
+	BirthPlace                  *string
+	ResidentAddress             *string
+	PortraitCaptureDate         *time.Time
+	AgeInYears                  *uint
+	AgeBirthYear                *uint
+	AgeOver                     map[uint]bool
+	IssuingJurisdiction         *string
+	Nationality                 *string
+	ResidentCity                *string
+	ResidentState               *string
+	ResidentPostalCode          *string
+	ResidentCountry             *string
+	FamilyNameNationalCharacter *string
+	GivenNameNationalCharacter  *string
+	SignatureUsualMark          []byte
+}
+
+// DrivingPrivilege is a vehicle category the holder is licensed for.
+type DrivingPrivilege struct {
+	VehicleCategoryCode string         `cbor:"vehicle_category_code"`
+	IssueDate           *mdoc.FullDate `cbor:"issue_date,omitempty"`
+	ExpiryDate          *mdoc.FullDate `cbor:"expiry_date,omitempty"`
+	Codes               []Code         `cbor:"codes,omitempty"`
+}
+
+// Code is a restriction or condition applied to a DrivingPrivilege.
+type Code struct {
+	Code  string `cbor:"code"`
+	Sign  string `cbor:"sign,omitempty"`
+	Value string `cbor:"value,omitempty"`
+}
+
+func (m *MDL) dataElements() map[mdoc.DataElementIdentifier]any {
+	return map[mdoc.DataElementIdentifier]any{
+		FamilyName:                  &m.FamilyName,
+		GivenName:                   &m.GivenName,
+		BirthDate:                   &m.BirthDate,
+		IssueDate:                   &m.IssueDate,
+		ExpiryDate:                  &m.ExpiryDate,
+		IssuingCountry:              &m.IssuingCountry,
+		IssuingAuthority:            &m.IssuingAuthority,
+		DocumentNumber:              &m.DocumentNumber,
+		Portrait:                    &m.Portrait,
+		DrivingPrivileges:           &m.DrivingPrivileges,
+		UNDistinguishingSign:        &m.UNDistinguishingSign,
+		AdministrativeNumber:        &m.AdministrativeNumber,
+		Sex:                         &m.Sex,
+		Height:                      &m.Height,
+		Weight:                      &m.Weight,
+		EyeColour:                   &m.EyeColour,
+		HairColour:                  &m.HairColour,
+		BirthPlace:                  &m.BirthPlace,
+		ResidentAddress:             &m.ResidentAddress,
+		PortraitCaptureDate:         &m.PortraitCaptureDate,
+		AgeInYears:                  &m.AgeInYears,
+		AgeBirthYear:                &m.AgeBirthYear,
+		IssuingJurisdiction:         &m.IssuingJurisdiction,
+		Nationality:                 &m.Nationality,
+		ResidentCity:                &m.ResidentCity,
+		ResidentState:               &m.ResidentState,
+		ResidentPostalCode:          &m.ResidentPostalCode,
+		ResidentCountry:             &m.ResidentCountry,
+		FamilyNameNationalCharacter: &m.FamilyNameNationalCharacter,
+		GivenNameNationalCharacter:  &m.GivenNameNationalCharacter,
+		SignatureUsualMark:          &m.SignatureUsualMark,
+	}
+}
+
+// Decode reads the mDL data elements from the org.iso.18013.5.1 name space.
+// Data elements not defined by the name space are ignored, and a data element present
+// more than once is rejected.
+func Decode(nameSpaces mdoc.IssuerNameSpaces) (*MDL, error) {
+	issuerSignedItemBytess, ok := nameSpaces[NameSpace]
+	if !ok {
+		return nil, ErrMissingNameSpace
+	}
+
+	m := new(MDL)
+	dataElements := m.dataElements()
+
+	seen := make(map[mdoc.DataElementIdentifier]struct{}, len(issuerSignedItemBytess))
+	for _, issuerSignedItemBytes := range issuerSignedItemBytess {
+		dataElementIdentifier, elementValue, err := issuerSignedItemBytes.RawElementValue()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := seen[dataElementIdentifier]; ok {
+			return nil, mdoc.ErrDuplicateDataElement
+		}
+		seen[dataElementIdentifier] = struct{}{}
+
+		if dataElement, ok := dataElements[dataElementIdentifier]; ok {
+			if err := mdoccbor.Unmarshal(elementValue, dataElement); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if age, ok := ParseAgeOver(dataElementIdentifier); ok {
+			var over bool
+			if err := mdoccbor.Unmarshal(elementValue, &over); err != nil {
+				return nil, err
+			}
+			if m.AgeOver == nil {
+				m.AgeOver = make(map[uint]bool)
+			}
+			m.AgeOver[age] = over
+		}
+	}
+
+	return m, nil
+}
+
+// IssuerNameSpaces encodes the present data elements as IssuerSignedItems in the
+// org.iso.18013.5.1 name space. The DigestIDs are unique and drawn from rand, so that they
+// reveal nothing about the data elements which are not disclosed.
+func (m *MDL) IssuerNameSpaces(rand io.Reader) (mdoc.IssuerNameSpaces, error) {
+	values, err := m.DataElementValues()
+	if err != nil {
+		return nil, err
+	}
+
+	dataElementIdentifiers := make([]mdoc.DataElementIdentifier, 0, len(values))
+	for dataElementIdentifier := range values {
+		dataElementIdentifiers = append(dataElementIdentifiers, dataElementIdentifier)
+	}
+	sort.Slice(dataElementIdentifiers, func(i, j int) bool { return dataElementIdentifiers[i] < dataElementIdentifiers[j] })
+
+	digestIDs, err := randomDigestIDs(rand, len(dataElementIdentifiers))
+	if err != nil {
+		return nil, err
+	}
+
+	issuerSignedItemBytess := make([]mdoc.IssuerSignedItemBytes, 0, len(values))
+	for i, dataElementIdentifier := range dataElementIdentifiers {
+		issuerSignedItemBytes, err := mdoc.NewIssuerSignedItemBytes(rand, digestIDs[i], dataElementIdentifier, values[dataElementIdentifier])
+		if err != nil {
+			return nil, err
+		}
+		issuerSignedItemBytess = append(issuerSignedItemBytess, *issuerSignedItemBytes)
+	}
+
+	return mdoc.IssuerNameSpaces{NameSpace: issuerSignedItemBytess}, nil
+}
+
+// randomDigestIDs draws n unique DigestIDs from rand.
+func randomDigestIDs(rand io.Reader, n int) ([]mdoc.DigestID, error) {
+	digestIDs := make([]mdoc.DigestID, 0, n)
+	seen := make(map[mdoc.DigestID]struct{}, n)
+
+	var b [4]byte
+	for len(digestIDs) < n {
+		if _, err := io.ReadFull(rand, b[:]); err != nil {
+			return nil, err
+		}
+
+		digestID := mdoc.DigestID(binary.BigEndian.Uint32(b[:]))
+		if _, ok := seen[digestID]; ok {
+			continue
+		}
+		seen[digestID] = struct{}{}
+		digestIDs = append(digestIDs, digestID)
+	}
+
+	return digestIDs, nil
+}
+
+// DataElementValues returns the present data elements, keyed by identifier.
+func (m *MDL) DataElementValues() (map[mdoc.DataElementIdentifier]mdoc.DataElementValue, error) {
+	values := make(map[mdoc.DataElementIdentifier]mdoc.DataElementValue)
+	for dataElementIdentifier, dataElement := range m.dataElements() {
+		if value, ok := present(dataElement); ok {
+			values[dataElementIdentifier] = value
+		}
+	}
+	for age, over := range m.AgeOver {
+		dataElementIdentifier, err := AgeOver(age)
+		if err != nil {
+			return nil, err
+		}
+		values[dataElementIdentifier] = over
+	}
+	return values, nil
+}
+
+func present(dataElement any) (any, bool) {
+	switch v := dataElement.(type) {
+	case **string:
+		if *v == nil {
+			return nil, false
+		}
+		return **v, true
+	case **uint:
+		if *v == nil {
+			return nil, false
+		}
+		return **v, true
+	case *[]byte:
+		return *v, *v != nil
+	case **mdoc.FullDate:
+		if *v == nil {
+			return nil, false
+		}
+		return **v, true
+	case **time.Time:
+		if *v == nil {
+			return nil, false
+		}
+		return &mdoc.TypedDataElementValue{CBORType: mdoccbor.CBORTypeTdate, Value: **v}, true
+	case *[]DrivingPrivilege:
+		return *v, *v != nil
+	}
+	return nil, false
+}
+
+// ParseAgeOver returns the age of an age_over_NN data element identifier.
+func ParseAgeOver(dataElementIdentifier mdoc.DataElementIdentifier) (uint, bool) {
+	nn, ok := strings.CutPrefix(string(dataElementIdentifier), ageOverPrefix)
+	if !ok || len(nn) != 2 {
+		return 0, false
+	}
+	age, err := strconv.ParseUint(nn, 10, 8)
+	if err != nil {
+		return 0, false
+	}
+	return uint(age), true
+}
@@ -0,0 +1,197 @@
+package mdl
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/alex-richards/go-mdoc/internal/testutil"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_MDL_RoundTrip(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	portraitCaptureDate := time.Date(2020, time.March, 4, 5, 6, 7, 0, time.UTC)
+	want := &MDL{
+		FamilyName:       ptr("Mustermann"),
+		GivenName:        ptr("Erika"),
+		BirthDate:        &mdoc.FullDate{Year: 1971, Month: time.September, Day: 1},
+		IssueDate:        &mdoc.FullDate{Year: 2020, Month: time.March, Day: 4},
+		ExpiryDate:       &mdoc.FullDate{Year: 2030, Month: time.March, Day: 3},
+		IssuingCountry:   ptr("NZ"),
+		IssuingAuthority: ptr("NZTA"),
+		DocumentNumber:   ptr("AB123456"),
+		Portrait:         []byte{0xff, 0xd8, 0xff, 0xd9},
+		DrivingPrivileges: []DrivingPrivilege{
+			{
+				VehicleCategoryCode: "A",
+				IssueDate:           &mdoc.FullDate{Year: 2018, Month: time.August, Day: 9},
+				ExpiryDate:          &mdoc.FullDate{Year: 2028, Month: time.September, Day: 1},
+			},
+			{
+				VehicleCategoryCode: "B",
+				Codes: []Code{
+					{Code: "01", Sign: "=", Value: "02"},
+					{Code: "78"},
+				},
+			},
+		},
+		UNDistinguishingSign: ptr("NZ"),
+		Sex:                  ptr[uint](2),
+		Height:               ptr[uint](170),
+		PortraitCaptureDate:  &portraitCaptureDate,
+		AgeInYears:           ptr[uint](50),
+		AgeOver:              map[uint]bool{18: true, 21: true, 65: false},
+	}
+
+	nameSpaces, err := want.IssuerNameSpaces(rand)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Decode(nameSpaces)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_MDL_RoundTrip_ZeroValues(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	want := &MDL{
+		GivenName:  ptr(""),
+		Sex:        ptr[uint](0),
+		AgeInYears: ptr[uint](0),
+		Portrait:   []byte{},
+	}
+
+	nameSpaces, err := want.IssuerNameSpaces(rand)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nameSpaces[NameSpace]) != 4 {
+		t.Fatalf("expected 4 data elements, got %d", len(nameSpaces[NameSpace]))
+	}
+
+	got, err := Decode(nameSpaces)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_Decode_Duplicate(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	nameSpaces, err := (&MDL{Sex: ptr[uint](1)}).IssuerNameSpaces(rand)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	duplicate, err := mdoc.NewIssuerSignedItemBytes(rand, 1, Sex, uint(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nameSpaces[NameSpace] = append(nameSpaces[NameSpace], *duplicate)
+
+	if _, err = Decode(nameSpaces); !errors.Is(err, mdoc.ErrDuplicateDataElement) {
+		t.Fatalf("expected %v, got %v", mdoc.ErrDuplicateDataElement, err)
+	}
+}
+
+func Test_Decode_MissingNameSpace(t *testing.T) {
+	if _, err := Decode(mdoc.IssuerNameSpaces{}); err != ErrMissingNameSpace {
+		t.Fatalf("expected %v, got %v", ErrMissingNameSpace, err)
+	}
+}
+
+func Test_AgeOver(t *testing.T) {
+	tests := []struct {
+		age     uint
+		want    mdoc.DataElementIdentifier
+		wantErr error
+	}{
+		{age: 18, want: "age_over_18"},
+		{age: 5, want: "age_over_05"},
+		{age: 99, want: "age_over_99"},
+		{age: 100, wantErr: ErrInvalidAgeOver},
+	}
+
+	for _, tt := range tests {
+		got, err := AgeOver(tt.age)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("expected %v, got %v", tt.wantErr, err)
+		}
+		if got != tt.want {
+			t.Fatalf("expected %s, got %s", tt.want, got)
+		}
+	}
+}
+
+func Test_MDL_RoundTrip_AgeOver(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	want := &MDL{AgeOver: map[uint]bool{0: true, 99: false}}
+
+	nameSpaces, err := want.IssuerNameSpaces(rand)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Decode(nameSpaces)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if _, err = (&MDL{AgeOver: map[uint]bool{100: true}}).IssuerNameSpaces(rand); !errors.Is(err, ErrInvalidAgeOver) {
+		t.Fatalf("expected %v, got %v", ErrInvalidAgeOver, err)
+	}
+}
+
+func Test_MDL_IssuerNameSpaces_DigestIDs(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	nameSpaces, err := (&MDL{
+		FamilyName: ptr("family"),
+		GivenName:  ptr("given"),
+		Sex:        ptr[uint](1),
+		AgeOver:    map[uint]bool{18: true, 21: true},
+	}).IssuerNameSpaces(rand)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digestIDs := make(map[mdoc.DigestID]struct{})
+	sequential := true
+	for i, issuerSignedItemBytes := range nameSpaces[NameSpace] {
+		issuerSignedItem, err := issuerSignedItemBytes.IssuerSignedItem()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := digestIDs[issuerSignedItem.DigestID]; ok {
+			t.Fatalf("duplicate digest ID %d", issuerSignedItem.DigestID)
+		}
+		digestIDs[issuerSignedItem.DigestID] = struct{}{}
+		sequential = sequential && issuerSignedItem.DigestID == mdoc.DigestID(i)
+	}
+	if sequential {
+		t.Fatal("expected random digest IDs")
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
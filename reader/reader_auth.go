@@ -37,16 +37,23 @@ func NewReaderAuth(
 	readerAuthority ReaderAuthority,
 	readerAuthenticationBytes *cbor.TaggedEncodedCBOR,
 ) (*mdoc.ReaderAuth, error) {
-	readerAuth := new(mdoc.ReaderAuth)
+	readerAuth := &mdoc.ReaderAuth{
+		Headers: cose.Headers{
+			Unprotected: cose.UnprotectedHeader{
+				cose.HeaderLabelX5Chain: readerAuthority.RootCertificate.Raw,
+			},
+		},
+	}
 
 	sign1 := (cose.Sign1Message)(*readerAuth)
 	sign1.Payload = readerAuthenticationBytes.TaggedValue
 
-	err := sign1.Sign(rand, []byte{}, mdoc.CoseSigner{readerAuthority.Signer})
+	err := sign1.Sign(rand, []byte{}, mdoc.CoseSigner{Signer: readerAuthority.Signer})
 	if err != nil {
 		return nil, err
 	}
 
+	readerAuth.Headers = sign1.Headers
 	readerAuth.Signature = sign1.Signature
 	return readerAuth, nil
 }
@@ -0,0 +1,268 @@
+package reader
+
+import (
+	"crypto/x509"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/alex-richards/go-mdoc/cipher_suite"
+	mdoccbor "github.com/alex-richards/go-mdoc/internal/cbor"
+	"github.com/alex-richards/go-mdoc/session"
+)
+
+var (
+	ErrUnexpectedSessionState = errors.New("mdoc: reader: unexpected session state")
+	ErrMissingSessionData     = errors.New("mdoc: reader: missing session data")
+)
+
+type SessionState int
+
+const (
+	SessionStateEngaged SessionState = iota
+	SessionStateEstablished
+	SessionStateTerminated
+)
+
+// Session drives the reader side of a device retrieval transaction, from a scanned
+// DeviceEngagement through to session termination.
+type Session struct {
+	rand             io.Reader
+	readerAuthority  *ReaderAuthority
+	rootCertificates []*x509.Certificate
+	policy           *mdoc.VerifierPolicy
+	options          session.SessionEncryptionOptions
+
+	state             SessionState
+	deviceEngagement  *mdoc.DeviceEngagement
+	eReaderKey        *mdoc.PrivateKey
+	sessionTranscript *mdoc.SessionTranscript
+	sessionEncryption *session.SessionEncryption
+	deviceRequest     *mdoc.DeviceRequest
+}
+
+// NewSession starts a session from the encoded DeviceEngagement received from the holder,
+// generating a new EReaderKey on the same curve as the EDeviceKey.
+// readerAuthority may be nil, in which case DocRequests are sent without ReaderAuth.
+// policy may be nil, in which case mdoc.DefaultVerifierPolicy is used.
+// options may be nil. Unless options sets a CipherSuite for the identifier in the
+// DeviceEngagement, the registered cipher suite is used, and
+// session.ErrUnsupportedCipherSuite is returned if there is none. With
+// RetainCounterOnFailure set, a response which fails to decrypt does not terminate the
+// session, so it may be resent. The DeviceEngagement and DeviceResponses are decoded
+// within DecodeLimits.
+func NewSession(
+	rand io.Reader,
+	deviceEngagementBytes []byte,
+	handover mdoc.Handover,
+	readerAuthority *ReaderAuthority,
+	rootCertificates []*x509.Certificate,
+	policy *mdoc.VerifierPolicy,
+	options *session.SessionEncryptionOptions,
+) (*Session, error) {
+	if policy == nil {
+		policy = mdoc.DefaultVerifierPolicy()
+	}
+	if options == nil {
+		options = new(session.SessionEncryptionOptions)
+	}
+
+	taggedDeviceEngagementBytes, err := mdoccbor.NewTaggedEncodedCBOR(deviceEngagementBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceEngagement := new(mdoc.DeviceEngagement)
+	if err = mdoc.UnmarshalWithLimits(deviceEngagementBytes, deviceEngagement, options.DecodeLimits); err != nil {
+		return nil, err
+	}
+
+	sessionOptions := *options
+	cipherSuiteIdentifier := deviceEngagement.Security.CipherSuiteIdentifier
+	if sessionOptions.CipherSuite == nil || sessionOptions.CipherSuite.Identifier() != cipherSuiteIdentifier {
+		if sessionOptions.CipherSuite, err = session.LookupCipherSuite(cipherSuiteIdentifier); err != nil {
+			return nil, err
+		}
+	}
+
+	eDeviceKey, err := deviceEngagement.EDeviceKey()
+	if err != nil {
+		return nil, err
+	}
+
+	curve, err := eDeviceKey.Curve()
+	if err != nil {
+		return nil, err
+	}
+
+	eReaderKey, err := cipher_suite.GeneratePrivateKey(rand, curve, false)
+	if err != nil {
+		return nil, err
+	}
+
+	eReaderKeyBytes, err := mdoccbor.MarshalToNewTaggedEncodedCBOR(&eReaderKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionTranscript := &mdoc.SessionTranscript{
+		DeviceEngagementBytes: taggedDeviceEngagementBytes,
+		EReaderKeyBytes:       eReaderKeyBytes,
+		Handover:              handover,
+	}
+
+	sessionTranscriptBytes, err := mdoc.NewSessionTranscriptBytes(sessionTranscript)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionEncryption, err := NewSessionEncryption(eReaderKey, eDeviceKey, sessionTranscriptBytes, &sessionOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		rand:              rand,
+		readerAuthority:   readerAuthority,
+		rootCertificates:  rootCertificates,
+		policy:            policy,
+		options:           sessionOptions,
+		state:             SessionStateEngaged,
+		deviceEngagement:  deviceEngagement,
+		eReaderKey:        eReaderKey,
+		sessionTranscript: sessionTranscript,
+		sessionEncryption: sessionEncryption,
+	}, nil
+}
+
+func (s *Session) State() SessionState {
+	return s.state
+}
+
+func (s *Session) DeviceEngagement() *mdoc.DeviceEngagement {
+	return s.deviceEngagement
+}
+
+func (s *Session) SessionTranscript() *mdoc.SessionTranscript {
+	return s.sessionTranscript
+}
+
+// SessionEstablishment creates the first message sent to the holder, containing the
+// EReaderKey and an encrypted DeviceRequest for itemsRequests.
+func (s *Session) SessionEstablishment(itemsRequests []*mdoc.ItemsRequest) (*session.SessionEstablishment, error) {
+	if s.state != SessionStateEngaged {
+		return nil, ErrUnexpectedSessionState
+	}
+
+	data, err := s.encryptDeviceRequest(itemsRequests)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionEstablishment, err := session.NewSessionEstablishment(&s.eReaderKey.PublicKey, data)
+	if err != nil {
+		return nil, err
+	}
+
+	s.state = SessionStateEstablished
+	return sessionEstablishment, nil
+}
+
+// SessionData creates a subsequent encrypted DeviceRequest for an established session.
+func (s *Session) SessionData(itemsRequests []*mdoc.ItemsRequest) (*session.SessionData, error) {
+	if s.state != SessionStateEstablished {
+		return nil, ErrUnexpectedSessionState
+	}
+
+	data, err := s.encryptDeviceRequest(itemsRequests)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session.SessionData{Data: data}, nil
+}
+
+// HandleSessionData decrypts and verifies a DeviceResponse received from the holder,
+// returning the verified claims.
+// Any status received from the holder terminates the session; a termination status sent
+// alongside data still returns the DeviceResponse.
+// When decryption or decoding fails the session is terminated, unless the response failed
+// to decrypt and RetainCounterOnFailure is set, and the returned error indicates which
+// status should be sent to the holder.
+func (s *Session) HandleSessionData(sessionData *session.SessionData, now time.Time) (*mdoc.VerifiedDeviceResponse, error) {
+	if s.state != SessionStateEstablished {
+		return nil, ErrUnexpectedSessionState
+	}
+
+	if sessionData.Data == nil {
+		s.terminate()
+		if err := sessionData.Status.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrMissingSessionData
+	}
+
+	deviceResponseBytes, err := s.sessionEncryption.Decrypt(sessionData.Data)
+	if err != nil {
+		if !s.options.RetainCounterOnFailure || !errors.Is(err, session.ErrSessionEncryption) {
+			s.terminate()
+		}
+		return nil, err
+	}
+
+	deviceResponse, err := mdoc.DecodeDeviceResponse(deviceResponseBytes, s.options.DecodeLimits)
+	if err != nil {
+		s.terminate()
+		return nil, session.ErrCBORDecoding
+	}
+
+	if sessionData.Status != 0 {
+		s.terminate()
+	}
+
+	return deviceResponse.Verify(s.policy, s.deviceRequest, s.rootCertificates, now, s.eReaderKey, s.sessionTranscript)
+}
+
+// Terminate ends the session, returning the SessionData to send to the holder.
+func (s *Session) Terminate() *session.SessionData {
+	s.terminate()
+	return &session.SessionData{Status: session.SessionStatusSessionTermination}
+}
+
+func (s *Session) terminate() {
+	s.state = SessionStateTerminated
+	s.sessionEncryption = nil
+}
+
+func (s *Session) encryptDeviceRequest(itemsRequests []*mdoc.ItemsRequest) ([]byte, error) {
+	docRequests := make([]mdoc.DocRequest, len(itemsRequests))
+	for i, itemsRequest := range itemsRequests {
+		var docRequest *mdoc.DocRequest
+		var err error
+		if s.readerAuthority != nil {
+			docRequest, err = NewAuthenticatedDocRequest(s.rand, *s.readerAuthority, itemsRequest, s.sessionTranscript)
+		} else {
+			docRequest, err = mdoc.NewDocRequest(itemsRequest)
+		}
+		if err != nil {
+			return nil, err
+		}
+		docRequests[i] = *docRequest
+	}
+
+	deviceRequest := mdoc.NewDeviceRequest(docRequests)
+	deviceRequestBytes, err := mdoccbor.Marshal(deviceRequest)
+	if err != nil {
+		return nil, err
+	}
+	s.deviceRequest = deviceRequest
+
+	data, err := s.sessionEncryption.Encrypt(deviceRequestBytes)
+	if err != nil {
+		s.terminate()
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -10,6 +10,7 @@ func NewSessionEncryption(
 	eReaderKey *mdoc.PrivateKey,
 	eDeviceKey *mdoc.PublicKey,
 	sessionTranscriptBytes *cbor.TaggedEncodedCBOR,
+	options *session.SessionEncryptionOptions,
 ) (*session.SessionEncryption, error) {
 	skReader, err := session.SKReader(eReaderKey.Agreer, eDeviceKey, sessionTranscriptBytes.TaggedValue)
 	if err != nil {
@@ -26,5 +27,6 @@ func NewSessionEncryption(
 		session.ReaderIdentifier,
 		skDevice,
 		session.DeviceIdentifier,
+		options,
 	)
 }
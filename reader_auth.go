@@ -13,7 +13,6 @@ import (
 	cose2 "github.com/alex-richards/go-mdoc/internal/cose"
 	mdocX509 "github.com/alex-richards/go-mdoc/internal/x509"
 
-	"github.com/fxamacker/cbor/v2"
 	"github.com/veraison/go-cose"
 )
 
@@ -27,10 +26,10 @@ var (
 type ReaderAuth cose.UntaggedSign1Message
 
 func (ra *ReaderAuth) MarshalCBOR() ([]byte, error) {
-	return cbor.Marshal((*cose.UntaggedSign1Message)(ra))
+	return cbor2.Marshal((*cose.UntaggedSign1Message)(ra))
 }
 func (ra *ReaderAuth) UnmarshalCBOR(data []byte) error {
-	return cbor.Unmarshal(data, (*cose.UntaggedSign1Message)(ra))
+	return cbor2.Unmarshal(data, (*cose.UntaggedSign1Message)(ra))
 }
 
 func (ra *ReaderAuth) Verify(
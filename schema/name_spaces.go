@@ -0,0 +1,91 @@
+package schema
+
+import (
+	"strings"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/alex-richards/go-mdoc/mdl"
+)
+
+const (
+	AAMVANameSpaceIdentifier mdoc.NameSpace = "org.iso.18013.5.1.aamva"
+
+	biometricTemplatePrefix = "biometric_template_"
+)
+
+// MDLNameSpace describes the org.iso.18013.5.1 name space.
+func MDLNameSpace() *NameSpace {
+	return &NameSpace{
+		NameSpace: mdl.NameSpace,
+		Elements: []Element{
+			{mdl.FamilyName, TypeTstr},
+			{mdl.GivenName, TypeTstr},
+			{mdl.BirthDate, TypeFullDate},
+			{mdl.IssueDate, TypeTdateOrFullDate},
+			{mdl.ExpiryDate, TypeTdateOrFullDate},
+			{mdl.IssuingCountry, TypeTstr},
+			{mdl.IssuingAuthority, TypeTstr},
+			{mdl.DocumentNumber, TypeTstr},
+			{mdl.Portrait, TypeBstr},
+			{mdl.DrivingPrivileges, TypeArray},
+			{mdl.UNDistinguishingSign, TypeTstr},
+			{mdl.AdministrativeNumber, TypeTstr},
+			{mdl.Sex, TypeUint},
+			{mdl.Height, TypeUint},
+			{mdl.Weight, TypeUint},
+			{mdl.EyeColour, TypeTstr},
+			{mdl.HairColour, TypeTstr},
+			{mdl.BirthPlace, TypeTstr},
+			{mdl.ResidentAddress, TypeTstr},
+			{mdl.PortraitCaptureDate, TypeTdate},
+			{mdl.AgeInYears, TypeUint},
+			{mdl.AgeBirthYear, TypeUint},
+			{mdl.IssuingJurisdiction, TypeTstr},
+			{mdl.Nationality, TypeTstr},
+			{mdl.ResidentCity, TypeTstr},
+			{mdl.ResidentState, TypeTstr},
+			{mdl.ResidentPostalCode, TypeTstr},
+			{mdl.ResidentCountry, TypeTstr},
+			{mdl.FamilyNameNationalCharacter, TypeTstr},
+			{mdl.GivenNameNationalCharacter, TypeTstr},
+			{mdl.SignatureUsualMark, TypeBstr},
+		},
+		Match: func(dataElementIdentifier mdoc.DataElementIdentifier) (Element, bool) {
+			if _, ok := mdl.ParseAgeOver(dataElementIdentifier); ok {
+				return Element{dataElementIdentifier, TypeBool}, true
+			}
+			if strings.HasPrefix(string(dataElementIdentifier), biometricTemplatePrefix) {
+				return Element{dataElementIdentifier, TypeBstr}, true
+			}
+			return Element{}, false
+		},
+	}
+}
+
+// AAMVANameSpace describes the AAMVA domestic org.iso.18013.5.1.aamva name space.
+func AAMVANameSpace() *NameSpace {
+	return &NameSpace{
+		NameSpace: AAMVANameSpaceIdentifier,
+		Elements: []Element{
+			{"domestic_driving_privileges", TypeArray},
+			{"name_suffix", TypeTstr},
+			{"organ_donor", TypeUint},
+			{"veteran", TypeUint},
+			{"family_name_truncation", TypeTstr},
+			{"given_name_truncation", TypeTstr},
+			{"aka_family_name.v2", TypeTstr},
+			{"aka_given_name.v2", TypeTstr},
+			{"aka_suffix", TypeTstr},
+			{"weight_range", TypeUint},
+			{"race_ethnicity", TypeTstr},
+			{"EDL_credential", TypeUint},
+			{"sex", TypeUint},
+			{"DHS_compliance", TypeTstr},
+			{"DHS_compliance_text", TypeTstr},
+			{"DHS_temporary_lawful_status", TypeUint},
+			{"resident_county", TypeTstr},
+			{"hazmat_endorsement_expiration_date", TypeFullDate},
+			{"CDL_indicator", TypeUint},
+		},
+	}
+}
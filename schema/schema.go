@@ -0,0 +1,220 @@
+package schema
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/alex-richards/go-mdoc"
+	mdoccbor "github.com/alex-richards/go-mdoc/internal/cbor"
+	"github.com/fxamacker/cbor/v2"
+)
+
+var (
+	ErrUnknownDataElement = errors.New("mdoc: schema: unknown data element")
+	ErrInvalidType        = errors.New("mdoc: schema: invalid type")
+)
+
+// Type is the CBOR type of a data element value.
+type Type string
+
+const (
+	TypeTstr            Type = "tstr"
+	TypeBstr            Type = "bstr"
+	TypeUint            Type = "uint"
+	TypeInt             Type = "int"
+	TypeBool            Type = "bool"
+	TypeTdate           Type = "tdate"
+	TypeFullDate        Type = "full-date"
+	TypeTdateOrFullDate Type = "tdate / full-date"
+	TypeArray           Type = "array"
+	TypeMap             Type = "map"
+	TypeAny             Type = "any"
+)
+
+const (
+	majorTypeUint   = 0
+	majorTypeNegInt = 1
+	majorTypeBstr   = 2
+	majorTypeTstr   = 3
+	majorTypeArray  = 4
+	majorTypeMap    = 5
+	majorTypeTag    = 6
+	majorTypeSimple = 7
+
+	simpleFalse = 0xf4
+	simpleTrue  = 0xf5
+)
+
+// Check reports whether the encoded value is of type t.
+func (t Type) Check(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+
+	majorType := data[0] >> 5
+	switch t {
+	case TypeTstr:
+		return majorType == majorTypeTstr
+	case TypeBstr:
+		return majorType == majorTypeBstr
+	case TypeUint:
+		return majorType == majorTypeUint
+	case TypeInt:
+		return majorType == majorTypeUint || majorType == majorTypeNegInt
+	case TypeBool:
+		return data[0] == simpleFalse || data[0] == simpleTrue
+	case TypeArray:
+		return majorType == majorTypeArray
+	case TypeMap:
+		return majorType == majorTypeMap
+	case TypeTdate:
+		return checkTag(data, mdoc.TagTDateString, majorTypeTstr)
+	case TypeFullDate:
+		return checkTag(data, mdoc.TagFullDateString, majorTypeTstr) ||
+			checkTag(data, mdoc.TagFullDateDays, majorTypeUint, majorTypeNegInt)
+	case TypeTdateOrFullDate:
+		return TypeTdate.Check(data) || TypeFullDate.Check(data)
+	case TypeAny:
+		return true
+	}
+
+	return false
+}
+
+func checkTag(data []byte, number uint64, contentMajorTypes ...byte) bool {
+	if data[0]>>5 != majorTypeTag {
+		return false
+	}
+
+	var tag cbor.RawTag
+	if err := mdoccbor.Unmarshal(data, &tag); err != nil {
+		return false
+	}
+	if tag.Number != number || len(tag.Content) == 0 {
+		return false
+	}
+
+	for _, contentMajorType := range contentMajorTypes {
+		if tag.Content[0]>>5 == contentMajorType {
+			return true
+		}
+	}
+	return false
+}
+
+// Element describes a data element in a name space.
+type Element struct {
+	Identifier mdoc.DataElementIdentifier
+	Type       Type
+}
+
+// NameSpace describes the data elements of a name space.
+type NameSpace struct {
+	NameSpace mdoc.NameSpace
+	Elements  []Element
+	// Match optionally describes data elements with variable identifiers, such as
+	// age_over_NN.
+	Match func(dataElementIdentifier mdoc.DataElementIdentifier) (Element, bool)
+}
+
+func (ns *NameSpace) lookup(dataElementIdentifier mdoc.DataElementIdentifier) (Element, bool) {
+	for _, element := range ns.Elements {
+		if element.Identifier == dataElementIdentifier {
+			return element, true
+		}
+	}
+	if ns.Match != nil {
+		return ns.Match(dataElementIdentifier)
+	}
+	return Element{}, false
+}
+
+// Registry holds the schemas of known name spaces.
+// Values in name spaces without a registered schema are not checked.
+type Registry struct {
+	mutex      sync.RWMutex
+	nameSpaces map[mdoc.NameSpace]*NameSpace
+}
+
+// NewRegistry creates a Registry containing nameSpaces.
+func NewRegistry(nameSpaces ...*NameSpace) *Registry {
+	registry := &Registry{
+		nameSpaces: make(map[mdoc.NameSpace]*NameSpace, len(nameSpaces)),
+	}
+	for _, nameSpace := range nameSpaces {
+		registry.Register(nameSpace)
+	}
+	return registry
+}
+
+// DefaultRegistry creates a Registry containing the mDL and AAMVA name spaces.
+func DefaultRegistry() *Registry {
+	return NewRegistry(MDLNameSpace(), AAMVANameSpace())
+}
+
+// Register adds or replaces the schema of a name space.
+func (r *Registry) Register(nameSpace *NameSpace) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.nameSpaces[nameSpace.NameSpace] = nameSpace
+}
+
+// Lookup returns the schema of a data element.
+func (r *Registry) Lookup(nameSpace mdoc.NameSpace, dataElementIdentifier mdoc.DataElementIdentifier) (Element, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	ns, ok := r.nameSpaces[nameSpace]
+	if !ok {
+		return Element{}, false
+	}
+	return ns.lookup(dataElementIdentifier)
+}
+
+// Validate checks a value will be encoded with the type registered for its data element.
+func (r *Registry) Validate(nameSpace mdoc.NameSpace, dataElementIdentifier mdoc.DataElementIdentifier, value mdoc.DataElementValue) error {
+	data, err := mdoccbor.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return r.ValidateCBOR(nameSpace, dataElementIdentifier, data)
+}
+
+// ValidateCBOR checks an encoded value has the type registered for its data element.
+func (r *Registry) ValidateCBOR(nameSpace mdoc.NameSpace, dataElementIdentifier mdoc.DataElementIdentifier, data []byte) error {
+	r.mutex.RLock()
+	ns, ok := r.nameSpaces[nameSpace]
+	r.mutex.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	element, ok := ns.lookup(dataElementIdentifier)
+	if !ok {
+		return ErrUnknownDataElement
+	}
+
+	if !element.Type.Check(data) {
+		return ErrInvalidType
+	}
+	return nil
+}
+
+// ValidateIssuerNameSpaces checks every IssuerSignedItem, returning the result for each
+// data element.
+func (r *Registry) ValidateIssuerNameSpaces(nameSpaces mdoc.IssuerNameSpaces) (mdoc.ElementResults, error) {
+	results := make(mdoc.ElementResults)
+	for nameSpace, issuerSignedItemBytess := range nameSpaces {
+		dataElements := make(map[mdoc.DataElementIdentifier]error, len(issuerSignedItemBytess))
+		results[nameSpace] = dataElements
+
+		for _, issuerSignedItemBytes := range issuerSignedItemBytess {
+			dataElementIdentifier, data, err := issuerSignedItemBytes.RawElementValue()
+			if err != nil {
+				return nil, err
+			}
+			dataElements[dataElementIdentifier] = r.ValidateCBOR(nameSpace, dataElementIdentifier, data)
+		}
+	}
+	return results, nil
+}
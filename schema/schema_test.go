@@ -0,0 +1,123 @@
+package schema
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/alex-richards/go-mdoc/internal/testutil"
+	"github.com/alex-richards/go-mdoc/mdl"
+)
+
+func Test_Type_Check(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  Type
+		data string
+		want bool
+	}{
+		{name: "tstr", typ: TypeTstr, data: "6161", want: true},
+		{name: "tstr bstr", typ: TypeTstr, data: "4161"},
+		{name: "bstr", typ: TypeBstr, data: "4161", want: true},
+		{name: "uint", typ: TypeUint, data: "1818", want: true},
+		{name: "uint negative", typ: TypeUint, data: "20"},
+		{name: "int negative", typ: TypeInt, data: "20", want: true},
+		{name: "bool true", typ: TypeBool, data: "f5", want: true},
+		{name: "bool false", typ: TypeBool, data: "f4", want: true},
+		{name: "bool null", typ: TypeBool, data: "f6"},
+		{name: "array", typ: TypeArray, data: "80", want: true},
+		{name: "map", typ: TypeMap, data: "a0", want: true},
+		{name: "tdate", typ: TypeTdate, data: "c074323032302d30332d30345430353a30363a30375a", want: true},
+		{name: "tdate untagged", typ: TypeTdate, data: "74323032302d30332d30345430353a30363a30375a"},
+		{name: "full-date string", typ: TypeFullDate, data: "d903ec6a313937312d30392d3031", want: true},
+		{name: "full-date days", typ: TypeFullDate, data: "d864190269", want: true},
+		{name: "full-date tdate", typ: TypeFullDate, data: "c074323032302d30332d30345430353a30363a30375a"},
+		{name: "tdate or full-date tdate", typ: TypeTdateOrFullDate, data: "c074323032302d30332d30345430353a30363a30375a", want: true},
+		{name: "tdate or full-date full-date", typ: TypeTdateOrFullDate, data: "d903ec6a313937312d30392d3031", want: true},
+		{name: "empty", typ: TypeAny, data: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tt.typ.Check(data); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_Registry_Validate(t *testing.T) {
+	registry := DefaultRegistry()
+
+	tests := []struct {
+		name                  string
+		nameSpace             mdoc.NameSpace
+		dataElementIdentifier mdoc.DataElementIdentifier
+		value                 mdoc.DataElementValue
+		wantErr               error
+	}{
+		{name: "tstr", nameSpace: mdl.NameSpace, dataElementIdentifier: mdl.FamilyName, value: "Mustermann"},
+		{name: "full-date", nameSpace: mdl.NameSpace, dataElementIdentifier: mdl.BirthDate, value: mdoc.FullDate{Year: 1971, Month: time.September, Day: 1}},
+		{name: "full-date as tstr", nameSpace: mdl.NameSpace, dataElementIdentifier: mdl.BirthDate, value: "1971-09-01", wantErr: ErrInvalidType},
+		{name: "age_over_NN", nameSpace: mdl.NameSpace, dataElementIdentifier: "age_over_18", value: true},
+		{name: "age_over_NN as uint", nameSpace: mdl.NameSpace, dataElementIdentifier: "age_over_18", value: 1, wantErr: ErrInvalidType},
+		{name: "unknown data element", nameSpace: mdl.NameSpace, dataElementIdentifier: "favourite_colour", value: "blue", wantErr: ErrUnknownDataElement},
+		{name: "aamva", nameSpace: AAMVANameSpaceIdentifier, dataElementIdentifier: "organ_donor", value: 1},
+		{name: "aamva wrong type", nameSpace: AAMVANameSpaceIdentifier, dataElementIdentifier: "organ_donor", value: "yes", wantErr: ErrInvalidType},
+		{name: "unknown name space", nameSpace: "org.example", dataElementIdentifier: "anything", value: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := registry.Validate(tt.nameSpace, tt.dataElementIdentifier, tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func Test_Registry_ValidateIssuerNameSpaces(t *testing.T) {
+	rand := testutil.NewDeterministicRand(t)
+
+	familyName := "Mustermann"
+	m := &mdl.MDL{
+		FamilyName: &familyName,
+		BirthDate:  &mdoc.FullDate{Year: 1971, Month: time.September, Day: 1},
+		DrivingPrivileges: []mdl.DrivingPrivilege{
+			{VehicleCategoryCode: "A"},
+		},
+		AgeOver: map[uint]bool{18: true},
+	}
+
+	nameSpaces, err := m.IssuerNameSpaces(rand)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrongType, err := mdoc.NewIssuerSignedItemBytes(rand, 99, mdl.Height, "tall")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nameSpaces[mdl.NameSpace] = append(nameSpaces[mdl.NameSpace], *wrongType)
+
+	results, err := DefaultRegistry().ValidateIssuerNameSpaces(nameSpaces)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dataElementIdentifier := range []mdoc.DataElementIdentifier{mdl.FamilyName, mdl.BirthDate, mdl.DrivingPrivileges, "age_over_18"} {
+		if !results.Valid(mdl.NameSpace, dataElementIdentifier) {
+			t.Fatalf("expected %s to be valid, got %v", dataElementIdentifier, results[mdl.NameSpace][dataElementIdentifier])
+		}
+	}
+	if err := results[mdl.NameSpace][mdl.Height]; !errors.Is(err, ErrInvalidType) {
+		t.Fatalf("expected %v, got %v", ErrInvalidType, err)
+	}
+}
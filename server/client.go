@@ -0,0 +1,90 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/alex-richards/go-mdoc"
+)
+
+var (
+	ErrUnexpectedStatus = errors.New("mdoc: server: unexpected HTTP status")
+)
+
+const (
+	maxResponseLength = 16 << 20
+)
+
+// Client performs WebAPI server retrieval on behalf of the reader. OIDC server retrieval is
+// not supported.
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient creates a Client, using http.DefaultClient when httpClient is nil.
+func NewClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{httpClient: httpClient}
+}
+
+// Retrieve requests itemsRequests from the WebAPI server retrieval method advertised in
+// the holder's DeviceEngagement.
+//
+// The returned documents are not verified: Retrieve does not check the JWS signatures or
+// the issuer certificates. The caller must verify each document before trusting it.
+func (c *Client) Retrieve(
+	ctx context.Context,
+	serverRetrievalMethods *mdoc.ServerRetrievalMethods,
+	itemsRequests []*mdoc.ItemsRequest,
+) (*ServerRetrievalResponse, error) {
+	if serverRetrievalMethods == nil || serverRetrievalMethods.WebAPI == nil {
+		return nil, ErrMissingWebAPI
+	}
+	webAPI := serverRetrievalMethods.WebAPI
+	if webAPI.Version != mdoc.ServerRetrievalVersion {
+		return nil, ErrUnsupportedVersion
+	}
+
+	requestBody, err := json.Marshal(NewServerRetrievalRequest(webAPI.ServerRetrievalToken, itemsRequests))
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webAPI.IssuerURL, bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, ErrInvalidToken
+	default:
+		return nil, ErrUnexpectedStatus
+	}
+
+	serverRetrievalResponse := new(ServerRetrievalResponse)
+	if err = json.NewDecoder(io.LimitReader(response.Body, maxResponseLength)).Decode(serverRetrievalResponse); err != nil {
+		return nil, err
+	}
+
+	if serverRetrievalResponse.Version != ServerRetrievalResponseVersion {
+		return nil, ErrUnsupportedVersion
+	}
+
+	return serverRetrievalResponse, nil
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+const (
+	maxRequestLength = 1 << 20
+)
+
+// RetrieveFunc returns the issuer signed documents, as JWS compact serializations, for the
+// docRequests authorized by token. It returns ErrInvalidToken if token is not recognised.
+type RetrieveFunc func(ctx context.Context, token string, docRequests []ServerDocRequest) ([]string, error)
+
+type handler struct {
+	retrieve RetrieveFunc
+}
+
+// NewHandler creates the issuer side WebAPI server retrieval endpoint.
+func NewHandler(retrieve RetrieveFunc) http.Handler {
+	return &handler{retrieve: retrieve}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	serverRetrievalRequest := new(ServerRetrievalRequest)
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestLength)).Decode(serverRetrievalRequest); err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if serverRetrievalRequest.Version != ServerRetrievalRequestVersion || len(serverRetrievalRequest.DocRequests) == 0 {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	documents, err := h.retrieve(r.Context(), serverRetrievalRequest.Token, serverRetrievalRequest.DocRequests)
+	switch {
+	case errors.Is(err, ErrInvalidToken):
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	case err != nil:
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(&ServerRetrievalResponse{
+		Version:   ServerRetrievalResponseVersion,
+		Documents: documents,
+	})
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/alex-richards/go-mdoc"
+)
+
+var (
+	ErrUnsupportedVersion = errors.New("mdoc: server: unsupported version")
+	ErrInvalidToken       = errors.New("mdoc: server: invalid server retrieval token")
+	ErrMissingWebAPI      = errors.New("mdoc: server: missing WebAPI server retrieval method")
+)
+
+const (
+	ServerRetrievalRequestVersion  = "1.0"
+	ServerRetrievalResponseVersion = "1.0"
+)
+
+// ServerRetrievalRequest is the WebAPI request sent by the reader to the issuer.
+type ServerRetrievalRequest struct {
+	Version     string             `json:"version"`
+	Token       string             `json:"token"`
+	DocRequests []ServerDocRequest `json:"docRequests"`
+}
+
+type ServerDocRequest struct {
+	DocType    mdoc.DocType    `json:"docType"`
+	NameSpaces mdoc.NameSpaces `json:"nameSpaces"`
+}
+
+// ServerRetrievalResponse is the WebAPI response returned by the issuer, each document is
+// a JWS in compact serialization signed by the issuer. go-mdoc does not verify the JWS;
+// that is left to the caller.
+type ServerRetrievalResponse struct {
+	Version   string   `json:"version"`
+	Documents []string `json:"documents,omitempty"`
+}
+
+func NewServerRetrievalRequest(token string, itemsRequests []*mdoc.ItemsRequest) *ServerRetrievalRequest {
+	docRequests := make([]ServerDocRequest, len(itemsRequests))
+	for i, itemsRequest := range itemsRequests {
+		docRequests[i] = ServerDocRequest{
+			DocType:    itemsRequest.DocType,
+			NameSpaces: itemsRequest.NameSpaces,
+		}
+	}
+
+	return &ServerRetrievalRequest{
+		Version:     ServerRetrievalRequestVersion,
+		Token:       token,
+		DocRequests: docRequests,
+	}
+}
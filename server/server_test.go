@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alex-richards/go-mdoc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Client_Handler(t *testing.T) {
+	itemsRequests := []*mdoc.ItemsRequest{{
+		DocType: "docType1",
+		NameSpaces: mdoc.NameSpaces{
+			"nameSpace1": {"dataElementIdentifier1": true},
+		},
+	}}
+
+	tests := []struct {
+		name    string
+		token   string
+		version uint
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "Valid token",
+			token:   "token1",
+			version: mdoc.ServerRetrievalVersion,
+			want:    []string{"document1"},
+		},
+		{
+			name:    "Invalid token",
+			token:   "token2",
+			version: mdoc.ServerRetrievalVersion,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "Unsupported version",
+			token:   "token1",
+			version: 2,
+			wantErr: ErrUnsupportedVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(NewHandler(func(ctx context.Context, token string, docRequests []ServerDocRequest) ([]string, error) {
+				if token != "token1" {
+					return nil, ErrInvalidToken
+				}
+				want := []ServerDocRequest{{DocType: itemsRequests[0].DocType, NameSpaces: itemsRequests[0].NameSpaces}}
+				if diff := cmp.Diff(want, docRequests); diff != "" {
+					t.Error(diff)
+				}
+				return []string{"document1"}, nil
+			}))
+			defer server.Close()
+
+			response, err := NewClient(server.Client()).Retrieve(
+				context.Background(),
+				&mdoc.ServerRetrievalMethods{
+					WebAPI: &mdoc.ServerRetrievalInformation{
+						Version:              tt.version,
+						IssuerURL:            server.URL,
+						ServerRetrievalToken: tt.token,
+					},
+				},
+				itemsRequests,
+			)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tt.want, response.Documents); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_Client_MissingWebAPI(t *testing.T) {
+	_, err := NewClient(nil).Retrieve(context.Background(), &mdoc.ServerRetrievalMethods{}, nil)
+	if !errors.Is(err, ErrMissingWebAPI) {
+		t.Fatalf("expected %v, got %v", ErrMissingWebAPI, err)
+	}
+}
+
+func Test_Handler_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		retrieve   RetrieveFunc
+		wantStatus int
+	}{
+		{
+			name:       "Wrong method",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "Invalid JSON",
+			method:     http.MethodPost,
+			body:       "{",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Unsupported version",
+			method:     http.MethodPost,
+			body:       `{"version":"2.0","token":"token1","docRequests":[{"docType":"docType1","nameSpaces":{}}]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "No doc requests",
+			method:     http.MethodPost,
+			body:       `{"version":"1.0","token":"token1","docRequests":[]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Retrieve error",
+			method: http.MethodPost,
+			body:   `{"version":"1.0","token":"token1","docRequests":[{"docType":"docType1","nameSpaces":{}}]}`,
+			retrieve: func(ctx context.Context, token string, docRequests []ServerDocRequest) ([]string, error) {
+				return nil, errors.New("failed")
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			NewHandler(tt.retrieve).ServeHTTP(recorder, httptest.NewRequest(tt.method, "/", strings.NewReader(tt.body)))
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, recorder.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package session
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"sync"
+
+	"github.com/alex-richards/go-mdoc"
+)
+
+var (
+	ErrUnsupportedCipherSuite = errors.New("mdoc: session: unsupported cipher suite")
+	ErrBuiltInCipherSuite     = errors.New("mdoc: session: cannot replace built-in cipher suite")
+)
+
+// CipherSuite encrypts session data for the Security.CipherSuiteIdentifier advertised in
+// the DeviceEngagement.
+type CipherSuite interface {
+	// Identifier is the Security.CipherSuiteIdentifier of the cipher suite.
+	Identifier() int
+
+	// NewAEAD creates the cipher keyed with a 32 byte SKReader or SKDevice. It must
+	// accept the 12 byte nonce of ISO 18013-5 9.1.1.5.
+	NewAEAD(sk []byte) (cipher.AEAD, error)
+}
+
+// CipherSuiteAES256GCM is cipher suite 1, AES-256-GCM, as defined by ISO 18013-5.
+var CipherSuiteAES256GCM CipherSuite = aes256GCM{}
+
+type aes256GCM struct{}
+
+func (aes256GCM) Identifier() int {
+	return mdoc.CipherSuiteVersion
+}
+
+func (aes256GCM) NewAEAD(sk []byte) (cipher.AEAD, error) {
+	if len(sk) != 32 {
+		return nil, aes.KeySizeError(len(sk))
+	}
+
+	block, err := aes.NewCipher(sk)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+var builtInCipherSuites = map[int]CipherSuite{
+	mdoc.CipherSuiteVersion: CipherSuiteAES256GCM,
+}
+
+var (
+	cipherSuitesMutex sync.RWMutex
+	cipherSuites      = make(map[int]CipherSuite)
+)
+
+// RegisterCipherSuite adds or replaces the cipher suite for its identifier, allowing
+// sessions to be established with devices advertising it. The cipher suites defined by
+// ISO 18013-5 cannot be replaced, and return ErrBuiltInCipherSuite.
+func RegisterCipherSuite(cipherSuite CipherSuite) error {
+	identifier := cipherSuite.Identifier()
+	if _, ok := builtInCipherSuites[identifier]; ok {
+		return ErrBuiltInCipherSuite
+	}
+
+	cipherSuitesMutex.Lock()
+	defer cipherSuitesMutex.Unlock()
+	cipherSuites[identifier] = cipherSuite
+	return nil
+}
+
+// LookupCipherSuite returns the registered cipher suite for a
+// Security.CipherSuiteIdentifier.
+func LookupCipherSuite(identifier int) (CipherSuite, error) {
+	if cipherSuite, ok := builtInCipherSuites[identifier]; ok {
+		return cipherSuite, nil
+	}
+
+	cipherSuitesMutex.RLock()
+	defer cipherSuitesMutex.RUnlock()
+
+	cipherSuite, ok := cipherSuites[identifier]
+	if !ok {
+		return nil, ErrUnsupportedCipherSuite
+	}
+	return cipherSuite, nil
+}
@@ -1,9 +1,17 @@
 package session
 
 import (
+	"errors"
+
 	"github.com/alex-richards/go-mdoc"
 	cbor2 "github.com/alex-richards/go-mdoc/internal/cbor"
-	"github.com/fxamacker/cbor/v2"
+)
+
+var (
+	ErrSessionEncryption  = errors.New("mdoc: session: session encryption error")
+	ErrCBORDecoding       = errors.New("mdoc: session: CBOR decoding error")
+	ErrSessionTermination = errors.New("mdoc: session: session terminated")
+	ErrUnknownStatus      = errors.New("mdoc: session: unknown status")
 )
 
 type SessionEstablishment struct {
@@ -23,9 +31,19 @@ func NewSessionEstablishment(eReaderKey *mdoc.PublicKey, data []byte) (*SessionE
 	}, nil
 }
 
+// DecodeSessionEstablishment decodes a SessionEstablishment received from a reader,
+// rejecting data exceeding limits, or the default limits if nil.
+func DecodeSessionEstablishment(data []byte, limits *mdoc.DecodeLimits) (*SessionEstablishment, error) {
+	sessionEstablishment := new(SessionEstablishment)
+	if err := mdoc.UnmarshalWithLimits(data, sessionEstablishment, limits); err != nil {
+		return nil, err
+	}
+	return sessionEstablishment, nil
+}
+
 func (se *SessionEstablishment) EReaderKey() (*mdoc.PublicKey, error) {
 	eReaderKey := new(mdoc.PublicKey)
-	if err := cbor.Unmarshal(se.EReaderKeyBytes.UntaggedValue, eReaderKey); err != nil {
+	if err := cbor2.Unmarshal(se.EReaderKeyBytes.UntaggedValue, eReaderKey); err != nil {
 		return nil, err
 	}
 
@@ -33,8 +51,18 @@ func (se *SessionEstablishment) EReaderKey() (*mdoc.PublicKey, error) {
 }
 
 type SessionData struct {
-	Data   []byte        `cbor:"data"`
-	Status SessionStatus `cbor:"status"`
+	Data   []byte        `cbor:"data,omitempty"`
+	Status SessionStatus `cbor:"status,omitempty"`
+}
+
+// DecodeSessionData decodes SessionData received from the other party, rejecting data
+// exceeding limits, or the default limits if nil.
+func DecodeSessionData(data []byte, limits *mdoc.DecodeLimits) (*SessionData, error) {
+	sessionData := new(SessionData)
+	if err := mdoc.UnmarshalWithLimits(data, sessionData, limits); err != nil {
+		return nil, err
+	}
+	return sessionData, nil
 }
 
 type SessionStatus uint
@@ -44,3 +72,35 @@ const (
 	SessionStatusErrorCBORDecoding      SessionStatus = 11
 	SessionStatusSessionTermination     SessionStatus = 20
 )
+
+// Err returns the error corresponding to a SessionStatus received from the other party,
+// or nil if no status was set.
+func (ss SessionStatus) Err() error {
+	switch ss {
+	case 0:
+		return nil
+	case SessionStatusErrorSessionEncryption:
+		return ErrSessionEncryption
+	case SessionStatusErrorCBORDecoding:
+		return ErrCBORDecoding
+	case SessionStatusSessionTermination:
+		return ErrSessionTermination
+	default:
+		return ErrUnknownStatus
+	}
+}
+
+// StatusForError returns the SessionStatus to send to the other party after err was
+// returned while handling SessionData.
+func StatusForError(err error) SessionStatus {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, ErrSessionEncryption), errors.Is(err, ErrCounterExhausted):
+		return SessionStatusErrorSessionEncryption
+	case errors.Is(err, ErrCBORDecoding):
+		return SessionStatusErrorCBORDecoding
+	default:
+		return SessionStatusSessionTermination
+	}
+}
@@ -82,3 +82,65 @@ func Test_SessionData_CBOR_RoundTrip(t *testing.T) {
 		t.Fatal(diff)
 	}
 }
+
+func Test_SessionStatus_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		status SessionStatus
+	}{
+		{name: "None", status: 0},
+		{name: "SessionEncryption", status: SessionStatusErrorSessionEncryption},
+		{name: "CBORDecoding", status: SessionStatusErrorCBORDecoding},
+		{name: "SessionTermination", status: SessionStatusSessionTermination},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := StatusForError(tt.status.Err()); status != tt.status {
+				t.Fatalf("expected %d, got %d", tt.status, status)
+			}
+		})
+	}
+}
+
+func FuzzDecodeSessionEstablishment(f *testing.F) {
+	eReaderKeyBytes, err := cbor2.NewTaggedEncodedCBOR([]byte{0xa1, 0x01, 0x02})
+	if err != nil {
+		f.Fatal(err)
+	}
+	seed, err := cbor2.Marshal(&SessionEstablishment{
+		EReaderKeyBytes: *eReaderKeyBytes,
+		Data:            []byte{1, 2, 3, 4},
+	})
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(seed)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		sessionEstablishment, err := DecodeSessionEstablishment(data, nil)
+		if err != nil {
+			return
+		}
+		_, _ = sessionEstablishment.EReaderKey()
+	})
+}
+
+func FuzzDecodeSessionData(f *testing.F) {
+	seed, err := cbor2.Marshal(&SessionData{
+		Data:   []byte{1, 2, 3, 4},
+		Status: SessionStatusSessionTermination,
+	})
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(seed)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		sessionData, err := DecodeSessionData(data, nil)
+		if err != nil {
+			return
+		}
+		_ = sessionData.Status.Err()
+	})
+}
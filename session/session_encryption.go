@@ -2,9 +2,11 @@ package session
 
 import (
 	"crypto"
-	"crypto/aes"
 	"crypto/cipher"
+	"encoding/binary"
+	"errors"
 	"io"
+	"math"
 
 	"github.com/alex-richards/go-mdoc"
 
@@ -17,6 +19,12 @@ const (
 
 	skDeviceLength = 32
 	skDeviceInfo   = "SKDevice"
+
+	nonceLength = 12
+)
+
+var (
+	ErrCounterExhausted = errors.New("mdoc: session: message counter exhausted")
 )
 
 var ReaderIdentifier = [8]byte{0, 0, 0, 0, 0, 0, 0, 0}
@@ -84,78 +92,110 @@ func sk(
 	return sk, nil
 }
 
+// SessionEncryptionOptions configures a SessionEncryption.
+type SessionEncryptionOptions struct {
+	// CipherSuite encrypts the session data, or CipherSuiteAES256GCM if nil.
+	CipherSuite CipherSuite
+
+	// RetainCounterOnFailure leaves the decryption counter unchanged when a message fails
+	// to authenticate, so that a corrupted message may be resent. By default the failed
+	// message is counted, and the next message must use the following counter.
+	RetainCounterOnFailure bool
+
+	// DecodeLimits bound the decoding of the messages a holder or reader session receives
+	// from the other party, or mdoc.DefaultDecodeLimits if nil.
+	DecodeLimits *mdoc.DecodeLimits
+}
+
+// SessionEncryption encrypts messages sent, and decrypts messages received, in order.
+// Each message uses the next counter in its nonce, ISO 18013-5 9.1.1.5; once the counter
+// is exhausted no further messages can be sent or received and a new session is needed.
 type SessionEncryption struct {
-	encryptionCipher     cipher.AEAD
-	encryptionIdentifier [8]byte
-	encryptionCounter    uint32
-	decryptionCipher     cipher.AEAD
-	decryptionIdentifier [8]byte
-	decryptionCounter    uint32
+	encryptionCipher       cipher.AEAD
+	encryptionIdentifier   [8]byte
+	encryptionCounter      uint32
+	decryptionCipher       cipher.AEAD
+	decryptionIdentifier   [8]byte
+	decryptionCounter      uint32
+	retainCounterOnFailure bool
 }
 
+// NewSessionEncryption creates a SessionEncryption. options may be nil, in which case
+// AES-256-GCM is used.
 func NewSessionEncryption(
 	encryptionSK []byte,
 	encryptionIdentifier [8]byte,
 	decryptionSK []byte,
 	decryptionIdentifier [8]byte,
+	options *SessionEncryptionOptions,
 ) (*SessionEncryption, error) {
-	encryptionBlockCipher, err := aes.NewCipher(encryptionSK)
-	if err != nil {
-		return nil, err
+	if options == nil {
+		options = new(SessionEncryptionOptions)
 	}
-	encryptionCipher, err := cipher.NewGCM(encryptionBlockCipher)
-	if err != nil {
-		return nil, err
+
+	cipherSuite := options.CipherSuite
+	if cipherSuite == nil {
+		cipherSuite = CipherSuiteAES256GCM
 	}
 
-	decryptionBlockCipher, err := aes.NewCipher(decryptionSK)
+	encryptionCipher, err := cipherSuite.NewAEAD(encryptionSK)
 	if err != nil {
 		return nil, err
 	}
-	decryptionCipher, err := cipher.NewGCM(decryptionBlockCipher)
+
+	decryptionCipher, err := cipherSuite.NewAEAD(decryptionSK)
 	if err != nil {
 		return nil, err
 	}
 
+	if encryptionCipher.NonceSize() != nonceLength || decryptionCipher.NonceSize() != nonceLength {
+		return nil, ErrUnsupportedCipherSuite
+	}
+
 	return &SessionEncryption{
-		encryptionCipher:     encryptionCipher,
-		encryptionIdentifier: encryptionIdentifier,
-		encryptionCounter:    0,
-		decryptionCipher:     decryptionCipher,
-		decryptionIdentifier: decryptionIdentifier,
-		decryptionCounter:    0,
+		encryptionCipher:       encryptionCipher,
+		encryptionIdentifier:   encryptionIdentifier,
+		encryptionCounter:      0,
+		decryptionCipher:       decryptionCipher,
+		decryptionIdentifier:   decryptionIdentifier,
+		decryptionCounter:      0,
+		retainCounterOnFailure: options.RetainCounterOnFailure,
 	}, nil
 }
 
-func (se *SessionEncryption) Encrypt(clearText []byte) []byte {
-	return se.encryptionCipher.Seal(nil, se.encryptNonce(), clearText, []byte{})
+// Encrypt encrypts the next message to send.
+func (se *SessionEncryption) Encrypt(clearText []byte) ([]byte, error) {
+	if se.encryptionCounter == math.MaxUint32 {
+		return nil, ErrCounterExhausted
+	}
+
+	se.encryptionCounter++
+	return se.encryptionCipher.Seal(nil, nonce(se.encryptionIdentifier, se.encryptionCounter), clearText, []byte{}), nil
 }
 
+// Decrypt decrypts the next message received, returning ErrSessionEncryption if it fails
+// to authenticate, including when a message was lost or received out of order.
 func (se *SessionEncryption) Decrypt(cipherText []byte) ([]byte, error) {
-	return se.decryptionCipher.Open(nil, se.decryptNonce(), cipherText, []byte{})
-}
+	if se.decryptionCounter == math.MaxUint32 {
+		return nil, ErrCounterExhausted
+	}
 
-func (se *SessionEncryption) encryptNonce() []byte {
-	se.encryptionCounter++
-	nonce := make([]byte, 12)
-	copy(nonce, se.encryptionIdentifier[:])
-	copy(nonce[8:], countToBytes(se.encryptionCounter))
-	return nonce
-}
+	counter := se.decryptionCounter + 1
+	clearText, err := se.decryptionCipher.Open(nil, nonce(se.decryptionIdentifier, counter), cipherText, []byte{})
+	if err != nil {
+		if !se.retainCounterOnFailure {
+			se.decryptionCounter = counter
+		}
+		return nil, ErrSessionEncryption
+	}
 
-func (se *SessionEncryption) decryptNonce() []byte {
-	se.decryptionCounter++
-	nonce := make([]byte, 12)
-	copy(nonce, se.decryptionIdentifier[:])
-	copy(nonce[8:], countToBytes(se.decryptionCounter))
-	return nonce
+	se.decryptionCounter = counter
+	return clearText, nil
 }
 
-func countToBytes(count uint32) []byte {
-	return []byte{
-		byte(count >> 24),
-		byte(count >> 16),
-		byte(count >> 8),
-		byte(count >> 0),
-	}
+func nonce(identifier [8]byte, counter uint32) []byte {
+	nonce := make([]byte, nonceLength)
+	copy(nonce, identifier[:])
+	binary.BigEndian.PutUint32(nonce[8:], counter)
+	return nonce
 }
@@ -0,0 +1,182 @@
+package session
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/alex-richards/go-mdoc"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+var (
+	testSKReader = bytes.Repeat([]byte{1}, 32)
+	testSKDevice = bytes.Repeat([]byte{2}, 32)
+)
+
+func newTestSessionEncryptions(t *testing.T, options *SessionEncryptionOptions) (*SessionEncryption, *SessionEncryption) {
+	t.Helper()
+
+	reader, err := NewSessionEncryption(testSKReader, ReaderIdentifier, testSKDevice, DeviceIdentifier, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	device, err := NewSessionEncryption(testSKDevice, DeviceIdentifier, testSKReader, ReaderIdentifier, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return reader, device
+}
+
+func Test_SessionEncryption_CounterExhausted(t *testing.T) {
+	reader, device := newTestSessionEncryptions(t, nil)
+	reader.encryptionCounter = math.MaxUint32 - 1
+	device.decryptionCounter = math.MaxUint32 - 1
+
+	cipherText, err := reader.Encrypt([]byte("lorem ipsum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = device.Decrypt(cipherText); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = reader.Encrypt([]byte("lorem ipsum")); !errors.Is(err, ErrCounterExhausted) {
+		t.Fatalf("expected %v, got %v", ErrCounterExhausted, err)
+	}
+	if _, err = device.Decrypt(cipherText); !errors.Is(err, ErrCounterExhausted) {
+		t.Fatalf("expected %v, got %v", ErrCounterExhausted, err)
+	}
+
+	if status := StatusForError(ErrCounterExhausted); status != SessionStatusErrorSessionEncryption {
+		t.Fatalf("expected %d, got %d", SessionStatusErrorSessionEncryption, status)
+	}
+}
+
+func Test_SessionEncryption_DecryptFailure(t *testing.T) {
+	tests := []struct {
+		name                   string
+		retainCounterOnFailure bool
+		want                   error
+	}{
+		{name: "AdvanceCounter", retainCounterOnFailure: false, want: ErrSessionEncryption},
+		{name: "RetainCounter", retainCounterOnFailure: true, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, device := newTestSessionEncryptions(t, &SessionEncryptionOptions{
+				RetainCounterOnFailure: tt.retainCounterOnFailure,
+			})
+
+			cipherText, err := reader.Encrypt([]byte("lorem ipsum"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			corrupted := bytes.Clone(cipherText)
+			corrupted[0] ^= 1
+			if _, err = device.Decrypt(corrupted); !errors.Is(err, ErrSessionEncryption) {
+				t.Fatalf("expected %v, got %v", ErrSessionEncryption, err)
+			}
+
+			if _, err = device.Decrypt(cipherText); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func Test_SessionEncryption_OutOfOrder(t *testing.T) {
+	reader, device := newTestSessionEncryptions(t, nil)
+
+	cipherText1, err := reader.Encrypt([]byte("lorem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cipherText2, err := reader.Encrypt([]byte("ipsum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = device.Decrypt(cipherText2)
+	if !errors.Is(err, ErrSessionEncryption) {
+		t.Fatalf("expected %v, got %v", ErrSessionEncryption, err)
+	}
+	if status := StatusForError(err); status != SessionStatusErrorSessionEncryption {
+		t.Fatalf("expected %d, got %d", SessionStatusErrorSessionEncryption, status)
+	}
+
+	if _, err = device.Decrypt(cipherText1); !errors.Is(err, ErrSessionEncryption) {
+		t.Fatalf("expected %v, got %v", ErrSessionEncryption, err)
+	}
+}
+
+type testCipherSuite struct {
+	identifier int
+	newAEAD    func(sk []byte) (cipher.AEAD, error)
+}
+
+func (cs testCipherSuite) Identifier() int {
+	return cs.identifier
+}
+
+func (cs testCipherSuite) NewAEAD(sk []byte) (cipher.AEAD, error) {
+	return cs.newAEAD(sk)
+}
+
+func Test_CipherSuite_Register(t *testing.T) {
+	if _, err := LookupCipherSuite(-1); !errors.Is(err, ErrUnsupportedCipherSuite) {
+		t.Fatalf("expected %v, got %v", ErrUnsupportedCipherSuite, err)
+	}
+
+	cipherSuite := testCipherSuite{identifier: -1, newAEAD: chacha20poly1305.New}
+	if err := RegisterCipherSuite(cipherSuite); err != nil {
+		t.Fatal(err)
+	}
+
+	registered, err := LookupCipherSuite(-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, device := newTestSessionEncryptions(t, &SessionEncryptionOptions{CipherSuite: registered})
+
+	cipherText, err := reader.Encrypt([]byte("lorem ipsum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clearText, err := device.Decrypt(cipherText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal([]byte("lorem ipsum"), clearText) {
+		t.Fatal()
+	}
+
+	_, err = NewSessionEncryption(testSKReader, ReaderIdentifier, testSKDevice, DeviceIdentifier, &SessionEncryptionOptions{
+		CipherSuite: testCipherSuite{identifier: -2, newAEAD: chacha20poly1305.NewX},
+	})
+	if !errors.Is(err, ErrUnsupportedCipherSuite) {
+		t.Fatalf("expected %v, got %v", ErrUnsupportedCipherSuite, err)
+	}
+}
+
+func Test_CipherSuite_Register_BuiltIn(t *testing.T) {
+	err := RegisterCipherSuite(testCipherSuite{identifier: mdoc.CipherSuiteVersion, newAEAD: chacha20poly1305.New})
+	if !errors.Is(err, ErrBuiltInCipherSuite) {
+		t.Fatalf("expected %v, got %v", ErrBuiltInCipherSuite, err)
+	}
+
+	cipherSuite, err := LookupCipherSuite(mdoc.CipherSuiteVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cipherSuite != CipherSuiteAES256GCM {
+		t.Fatalf("expected %v, got %v", CipherSuiteAES256GCM, cipherSuite)
+	}
+}
@@ -18,6 +18,19 @@ type SessionTranscript struct {
 	Handover              Handover
 }
 
+func NewSessionTranscriptBytes(sessionTranscript *SessionTranscript) (*mdoccbor.TaggedEncodedCBOR, error) {
+	return mdoccbor.MarshalToNewTaggedEncodedCBOR(sessionTranscript)
+}
+
+func (st *SessionTranscript) EReaderKey() (*PublicKey, error) {
+	eReaderKey := new(PublicKey)
+	if err := mdoccbor.Unmarshal(st.EReaderKeyBytes.UntaggedValue, eReaderKey); err != nil {
+		return nil, err
+	}
+
+	return eReaderKey, nil
+}
+
 type intermediateSessionTranscript struct {
 	_                     struct{} `cbor:",toarray"`
 	DeviceEngagementBytes *mdoccbor.TaggedEncodedCBOR
@@ -31,12 +44,12 @@ func (st *SessionTranscript) MarshalCBOR() ([]byte, error) {
 
 	switch handover := st.Handover.(type) {
 	case QRHandover:
-		handoverBytes, err = cbor.Marshal(&handover)
+		handoverBytes, err = mdoccbor.Marshal(&handover)
 		if err != nil {
 			return nil, err
 		}
 	case NFCHandover:
-		handoverBytes, err = cbor.Marshal(&handover)
+		handoverBytes, err = mdoccbor.Marshal(&handover)
 		if err != nil {
 			return nil, err
 		}
@@ -50,20 +63,20 @@ func (st *SessionTranscript) MarshalCBOR() ([]byte, error) {
 		Handover:              handoverBytes,
 	}
 
-	return cbor.Marshal(&intermediateSessionTranscript)
+	return mdoccbor.Marshal(&intermediateSessionTranscript)
 }
 
 func (st *SessionTranscript) UnmarshalCBOR(data []byte) error {
 	var err error
 
 	var intermediateSessionTranscript intermediateSessionTranscript
-	if err = cbor.Unmarshal(data, &intermediateSessionTranscript); err != nil {
+	if err = mdoccbor.Unmarshal(data, &intermediateSessionTranscript); err != nil {
 		return err
 	}
 
 	{
 		var qrHandover QRHandover
-		if err = cbor.Unmarshal(intermediateSessionTranscript.Handover, &qrHandover); err == nil {
+		if err = mdoccbor.Unmarshal(intermediateSessionTranscript.Handover, &qrHandover); err == nil {
 			st.DeviceEngagementBytes = intermediateSessionTranscript.DeviceEngagementBytes
 			st.EReaderKeyBytes = intermediateSessionTranscript.EReaderKeyBytes
 			st.Handover = qrHandover
@@ -73,7 +86,7 @@ func (st *SessionTranscript) UnmarshalCBOR(data []byte) error {
 
 	{
 		var nfcHandover NFCHandover
-		if err = cbor.Unmarshal(intermediateSessionTranscript.Handover, &nfcHandover); err == nil {
+		if err = mdoccbor.Unmarshal(intermediateSessionTranscript.Handover, &nfcHandover); err == nil {
 			st.DeviceEngagementBytes = intermediateSessionTranscript.DeviceEngagementBytes
 			st.EReaderKeyBytes = intermediateSessionTranscript.EReaderKeyBytes
 			st.Handover = nfcHandover
@@ -0,0 +1,195 @@
+package transport
+
+import (
+	"errors"
+
+	"github.com/alex-richards/go-mdoc/util"
+)
+
+var (
+	ErrMTUTooSmall              = errors.New("mdoc: transport: ble: MTU too small")
+	ErrInvalidFrame             = errors.New("mdoc: transport: ble: invalid frame")
+	ErrInvalidState             = errors.New("mdoc: transport: ble: invalid state")
+	ErrUnexpectedCharacteristic = errors.New("mdoc: transport: ble: unexpected characteristic")
+)
+
+var (
+	BLEPeripheralServerStateUUID         = util.UUID{0x00, 0x00, 0x00, 0x01, 0xa1, 0x23, 0x48, 0xce, 0x89, 0x6b, 0x4c, 0x76, 0x97, 0x33, 0x73, 0xe6}
+	BLEPeripheralServerClient2ServerUUID = util.UUID{0x00, 0x00, 0x00, 0x02, 0xa1, 0x23, 0x48, 0xce, 0x89, 0x6b, 0x4c, 0x76, 0x97, 0x33, 0x73, 0xe6}
+	BLEPeripheralServerServer2ClientUUID = util.UUID{0x00, 0x00, 0x00, 0x03, 0xa1, 0x23, 0x48, 0xce, 0x89, 0x6b, 0x4c, 0x76, 0x97, 0x33, 0x73, 0xe6}
+	BLEPeripheralServerIdentUUID         = util.UUID{0x00, 0x00, 0x00, 0x08, 0xa1, 0x23, 0x48, 0xce, 0x89, 0x6b, 0x4c, 0x76, 0x97, 0x33, 0x73, 0xe6}
+
+	BLECentralClientStateUUID         = util.UUID{0x00, 0x00, 0x00, 0x05, 0xa1, 0x23, 0x48, 0xce, 0x89, 0x6b, 0x4c, 0x76, 0x97, 0x33, 0x73, 0xe6}
+	BLECentralClientClient2ServerUUID = util.UUID{0x00, 0x00, 0x00, 0x06, 0xa1, 0x23, 0x48, 0xce, 0x89, 0x6b, 0x4c, 0x76, 0x97, 0x33, 0x73, 0xe6}
+	BLECentralClientServer2ClientUUID = util.UUID{0x00, 0x00, 0x00, 0x07, 0xa1, 0x23, 0x48, 0xce, 0x89, 0x6b, 0x4c, 0x76, 0x97, 0x33, 0x73, 0xe6}
+)
+
+type BLECharacteristic int
+
+const (
+	BLECharacteristicState BLECharacteristic = iota
+	BLECharacteristicClient2Server
+	BLECharacteristicServer2Client
+)
+
+// BLERole is the GATT role taken by this side of the connection. In mdoc peripheral server
+// mode the holder is the GATT server, in mdoc central client mode the reader is.
+type BLERole int
+
+const (
+	BLERoleGATTClient BLERole = iota
+	BLERoleGATTServer
+)
+
+const (
+	bleStateStart byte = 0x01
+	bleStateEnd   byte = 0x02
+
+	bleFrameLast byte = 0x00
+	bleFrameMore byte = 0x01
+
+	// ATT write and notification header.
+	bleATTHeaderLength = 3
+)
+
+// BLEPipe is the connection to the platform BLE stack, carrying values written to or
+// notified on the mdoc service characteristics.
+type BLEPipe interface {
+	// MTU returns the negotiated ATT MTU.
+	MTU() int
+	// Write writes or notifies value on characteristic.
+	Write(characteristic BLECharacteristic, value []byte) error
+	// Read blocks until a value is written to or notified on any characteristic by the
+	// other party.
+	Read() (BLECharacteristic, []byte, error)
+	Close() error
+}
+
+// BLE implements Transport over a BLEPipe, splitting messages into MTU sized frames on the
+// Client2Server and Server2Client characteristics.
+type BLE struct {
+	pipe BLEPipe
+	role BLERole
+
+	// MaxMessageLength is the longest message Receive accepts, or DefaultMaxMessageLength
+	// if zero.
+	MaxMessageLength int
+}
+
+var _ Transport = (*BLE)(nil)
+
+func NewBLE(pipe BLEPipe, role BLERole) *BLE {
+	return &BLE{
+		pipe: pipe,
+		role: role,
+	}
+}
+
+// Start writes the start command to the State characteristic, and should be called by the
+// GATT client once subscribed.
+func (b *BLE) Start() error {
+	return b.pipe.Write(BLECharacteristicState, []byte{bleStateStart})
+}
+
+func (b *BLE) Send(message []byte) error {
+	frameLength := b.pipe.MTU() - bleATTHeaderLength - 1
+	if frameLength < 1 {
+		return ErrMTUTooSmall
+	}
+
+	characteristic := b.sendCharacteristic()
+	for {
+		frame := make([]byte, 0, frameLength+1)
+		if len(message) > frameLength {
+			frame = append(frame, bleFrameMore)
+			frame = append(frame, message[:frameLength]...)
+			message = message[frameLength:]
+		} else {
+			frame = append(frame, bleFrameLast)
+			frame = append(frame, message...)
+			message = nil
+		}
+
+		if err := b.pipe.Write(characteristic, frame); err != nil {
+			return err
+		}
+
+		if message == nil {
+			return nil
+		}
+	}
+}
+
+// Receive reassembles the next message from its frames, returning ErrMessageTooLong once
+// it exceeds MaxMessageLength.
+func (b *BLE) Receive() ([]byte, error) {
+	characteristic := b.receiveCharacteristic()
+
+	maxMessageLength := b.MaxMessageLength
+	if maxMessageLength == 0 {
+		maxMessageLength = DefaultMaxMessageLength
+	}
+
+	var message []byte
+	for {
+		readCharacteristic, value, err := b.pipe.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		switch readCharacteristic {
+		case BLECharacteristicState:
+			if len(value) != 1 {
+				return nil, ErrInvalidState
+			}
+			switch value[0] {
+			case bleStateStart:
+				continue
+			case bleStateEnd:
+				return nil, ErrSessionEnded
+			default:
+				return nil, ErrInvalidState
+			}
+
+		case characteristic:
+			if len(value) == 0 {
+				return nil, ErrInvalidFrame
+			}
+			if len(message)+len(value)-1 > maxMessageLength {
+				return nil, ErrMessageTooLong
+			}
+			message = append(message, value[1:]...)
+			switch value[0] {
+			case bleFrameMore:
+				continue
+			case bleFrameLast:
+				return message, nil
+			default:
+				return nil, ErrInvalidFrame
+			}
+
+		default:
+			return nil, ErrUnexpectedCharacteristic
+		}
+	}
+}
+
+// Close writes the End command to the State characteristic and closes the pipe.
+func (b *BLE) Close() error {
+	err := b.pipe.Write(BLECharacteristicState, []byte{bleStateEnd})
+	return errors.Join(err, b.pipe.Close())
+}
+
+func (b *BLE) sendCharacteristic() BLECharacteristic {
+	if b.role == BLERoleGATTClient {
+		return BLECharacteristicClient2Server
+	}
+	return BLECharacteristicServer2Client
+}
+
+func (b *BLE) receiveCharacteristic() BLECharacteristic {
+	if b.role == BLERoleGATTClient {
+		return BLECharacteristicServer2Client
+	}
+	return BLECharacteristicClient2Server
+}
@@ -0,0 +1,208 @@
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+type fakeBLEValue struct {
+	characteristic BLECharacteristic
+	value          []byte
+}
+
+// fakeBLEPipe is one end of an in-memory radio link, recording every frame it writes.
+type fakeBLEPipe struct {
+	mtu     int
+	in      chan fakeBLEValue
+	out     chan fakeBLEValue
+	written []fakeBLEValue
+}
+
+func newFakeBLERadio(mtu int) (client *fakeBLEPipe, server *fakeBLEPipe) {
+	clientToServer := make(chan fakeBLEValue, 1024)
+	serverToClient := make(chan fakeBLEValue, 1024)
+	return &fakeBLEPipe{mtu: mtu, in: serverToClient, out: clientToServer},
+		&fakeBLEPipe{mtu: mtu, in: clientToServer, out: serverToClient}
+}
+
+func (p *fakeBLEPipe) MTU() int {
+	return p.mtu
+}
+
+func (p *fakeBLEPipe) Write(characteristic BLECharacteristic, value []byte) error {
+	if len(value) > p.mtu-bleATTHeaderLength {
+		return errors.New("value exceeds MTU")
+	}
+	v := fakeBLEValue{characteristic, append([]byte{}, value...)}
+	p.written = append(p.written, v)
+	p.out <- v
+	return nil
+}
+
+func (p *fakeBLEPipe) Read() (BLECharacteristic, []byte, error) {
+	v, ok := <-p.in
+	if !ok {
+		return 0, nil, io.EOF
+	}
+	return v.characteristic, v.value, nil
+}
+
+func (p *fakeBLEPipe) Close() error {
+	close(p.out)
+	return nil
+}
+
+func Test_BLE_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		mtu        int
+		length     int
+		wantFrames int
+	}{
+		{name: "Empty", mtu: 23, length: 0, wantFrames: 1},
+		{name: "Single frame", mtu: 23, length: 19, wantFrames: 1},
+		{name: "Two frames", mtu: 23, length: 20, wantFrames: 2},
+		{name: "Many frames", mtu: 23, length: 19 * 10, wantFrames: 10},
+		{name: "Large MTU", mtu: 517, length: 1000, wantFrames: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientPipe, serverPipe := newFakeBLERadio(tt.mtu)
+			client := NewBLE(clientPipe, BLERoleGATTClient)
+			server := NewBLE(serverPipe, BLERoleGATTServer)
+
+			if err := client.Start(); err != nil {
+				t.Fatal(err)
+			}
+
+			request := make([]byte, tt.length)
+			for i := range request {
+				request[i] = byte(i)
+			}
+
+			if err := client.Send(request); err != nil {
+				t.Fatal(err)
+			}
+
+			if frames := len(clientPipe.written) - 1; frames != tt.wantFrames {
+				t.Fatalf("expected %d frames, got %d", tt.wantFrames, frames)
+			}
+			for i, written := range clientPipe.written[1:] {
+				if written.characteristic != BLECharacteristicClient2Server {
+					t.Fatal("expected Client2Server")
+				}
+				wantContinuation := bleFrameMore
+				if i == tt.wantFrames-1 {
+					wantContinuation = bleFrameLast
+				}
+				if written.value[0] != wantContinuation {
+					t.Fatalf("expected continuation %x, got %x", wantContinuation, written.value[0])
+				}
+			}
+
+			received, err := server.Receive()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(request, received) {
+				t.Fatal("request mismatch")
+			}
+
+			if err = server.Send(request); err != nil {
+				t.Fatal(err)
+			}
+			if serverPipe.written[0].characteristic != BLECharacteristicServer2Client {
+				t.Fatal("expected Server2Client")
+			}
+
+			received, err = client.Receive()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(request, received) {
+				t.Fatal("response mismatch")
+			}
+
+			if err = client.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if _, err = server.Receive(); !errors.Is(err, ErrSessionEnded) {
+				t.Fatalf("expected %v, got %v", ErrSessionEnded, err)
+			}
+		})
+	}
+}
+
+func Test_BLE_Receive_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		characteristic BLECharacteristic
+		value          []byte
+		want           error
+	}{
+		{name: "Empty frame", characteristic: BLECharacteristicClient2Server, value: []byte{}, want: ErrInvalidFrame},
+		{name: "Bad continuation", characteristic: BLECharacteristicClient2Server, value: []byte{0x02, 0x00}, want: ErrInvalidFrame},
+		{name: "Bad state", characteristic: BLECharacteristicState, value: []byte{0x03}, want: ErrInvalidState},
+		{name: "Wrong characteristic", characteristic: BLECharacteristicServer2Client, value: []byte{0x00}, want: ErrUnexpectedCharacteristic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientPipe, serverPipe := newFakeBLERadio(23)
+			server := NewBLE(serverPipe, BLERoleGATTServer)
+
+			if err := clientPipe.Write(tt.characteristic, tt.value); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := server.Receive(); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func Test_BLE_Send_MTUTooSmall(t *testing.T) {
+	clientPipe, _ := newFakeBLERadio(4)
+	client := NewBLE(clientPipe, BLERoleGATTClient)
+
+	if err := client.Send([]byte{1}); !errors.Is(err, ErrMTUTooSmall) {
+		t.Fatalf("expected %v, got %v", ErrMTUTooSmall, err)
+	}
+}
+
+func Test_BLE_Receive_MessageTooLong(t *testing.T) {
+	tests := []struct {
+		name             string
+		maxMessageLength int
+		length           int
+		wantErr          error
+	}{
+		{name: "At maximum", maxMessageLength: 40, length: 40},
+		{name: "Over maximum", maxMessageLength: 40, length: 41, wantErr: ErrMessageTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientPipe, serverPipe := newFakeBLERadio(23)
+			client := NewBLE(clientPipe, BLERoleGATTClient)
+			server := NewBLE(serverPipe, BLERoleGATTServer)
+			server.MaxMessageLength = tt.maxMessageLength
+
+			if err := client.Send(make([]byte, tt.length)); err != nil {
+				t.Fatal(err)
+			}
+
+			message, err := server.Receive()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && len(message) != tt.length {
+				t.Fatalf("expected %d, got %d", tt.length, len(message))
+			}
+		})
+	}
+}
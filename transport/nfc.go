@@ -0,0 +1,285 @@
+package transport
+
+import (
+	"errors"
+
+	"github.com/alex-richards/go-mdoc"
+)
+
+var (
+	ErrInvalidAPDU          = errors.New("mdoc: transport: nfc: invalid APDU")
+	ErrUnexpectedStatusWord = errors.New("mdoc: transport: nfc: unexpected status word")
+	ErrInvalidTLV           = errors.New("mdoc: transport: nfc: invalid BER-TLV")
+	ErrMissingResponse      = errors.New("mdoc: transport: nfc: missing response")
+	ErrInvalidMaxLength     = errors.New("mdoc: transport: nfc: invalid max length")
+)
+
+// NFCAID is the application identifier of the mdoc NFC data retrieval application.
+var NFCAID = []byte{0xa0, 0x00, 0x00, 0x02, 0x48, 0x04, 0x00}
+
+const (
+	apduCLA         byte = 0x00
+	apduCLAChaining byte = 0x10
+
+	apduINSSelect      byte = 0xa4
+	apduINSEnvelope    byte = 0xc3
+	apduINSGetResponse byte = 0xc0
+
+	apduSW1MoreData byte = 0x61
+
+	apduShortMaxLc = 0xff
+	apduShortMaxLe = 0x100
+
+	apduExtendedMaxLc = 0xffff
+	apduExtendedMaxLe = 0x10000
+
+	tlvTagData byte = 0x53
+
+	// tag, 0x84 and four length bytes
+	tlvMaxHeaderLength = 6
+)
+
+var apduSWSuccess = [2]byte{0x90, 0x00}
+
+// APDUExchanger transmits a command APDU to the holder device and returns the response
+// APDU, including the trailing status word.
+type APDUExchanger interface {
+	Exchange(command []byte) ([]byte, error)
+}
+
+// NFC implements the reader side of NFC data retrieval, wrapping each message in a BER-TLV
+// data object sent with ENVELOPE commands.
+// As APDUs are command-response pairs, each Send must be followed by a Receive.
+type NFC struct {
+	exchanger             APDUExchanger
+	maxLengthCommandData  int
+	maxLengthResponseData int
+	extended              bool
+
+	// MaxMessageLength is the longest response Send accepts, or DefaultMaxMessageLength if
+	// zero.
+	MaxMessageLength int
+
+	response []byte
+}
+
+var _ Transport = (*NFC)(nil)
+
+// NewNFC creates an NFC transport with the limits advertised in the holder's NFCOptions.
+func NewNFC(exchanger APDUExchanger, options mdoc.NFCOptions) (*NFC, error) {
+	maxLengthCommandData := int(options.MaxLengthCommandData)
+	maxLengthResponseData := int(options.MaxLengthResponseData)
+	if maxLengthCommandData < 1 || maxLengthCommandData > apduExtendedMaxLc ||
+		maxLengthResponseData < 1 || maxLengthResponseData > apduExtendedMaxLe {
+		return nil, ErrInvalidMaxLength
+	}
+
+	return &NFC{
+		exchanger:             exchanger,
+		maxLengthCommandData:  maxLengthCommandData,
+		maxLengthResponseData: maxLengthResponseData,
+		extended:              maxLengthCommandData > apduShortMaxLc || maxLengthResponseData > apduShortMaxLe,
+	}, nil
+}
+
+// Select selects the mdoc application, and must be called before the first Send.
+func (n *NFC) Select() error {
+	command := []byte{apduCLA, apduINSSelect, 0x04, 0x0c, byte(len(NFCAID))}
+	command = append(command, NFCAID...)
+
+	response, err := n.exchanger.Exchange(command)
+	if err != nil {
+		return err
+	}
+
+	_, err = checkAPDUResponse(response)
+	return err
+}
+
+// Send sends message in one or more chained ENVELOPE commands, collecting the response
+// with GET RESPONSE as required. ErrMessageTooLong is returned once the response exceeds
+// MaxMessageLength.
+func (n *NFC) Send(message []byte) error {
+	n.response = nil
+
+	maxMessageLength := n.MaxMessageLength
+	if maxMessageLength == 0 {
+		maxMessageLength = DefaultMaxMessageLength
+	}
+
+	data := appendTLV(nil, tlvTagData, message)
+
+	var response []byte
+	for {
+		cla := apduCLA
+		chunk := data
+		if len(chunk) > n.maxLengthCommandData {
+			cla = apduCLAChaining
+			chunk = chunk[:n.maxLengthCommandData]
+		}
+		data = data[len(chunk):]
+
+		last := cla == apduCLA
+		apduResponse, err := n.exchanger.Exchange(n.command(cla, apduINSEnvelope, chunk, last))
+		if err != nil {
+			return err
+		}
+
+		if last {
+			response = apduResponse
+			break
+		}
+
+		if _, err = checkAPDUResponse(apduResponse); err != nil {
+			return err
+		}
+	}
+
+	var responseData []byte
+	for {
+		if len(response) < 2 {
+			return ErrInvalidAPDU
+		}
+		sw1 := response[len(response)-2]
+		if sw1 != apduSW1MoreData {
+			break
+		}
+		responseData = append(responseData, response[:len(response)-2]...)
+		if len(responseData) > maxMessageLength+tlvMaxHeaderLength {
+			return ErrMessageTooLong
+		}
+
+		var err error
+		response, err = n.exchanger.Exchange(n.command(apduCLA, apduINSGetResponse, nil, true))
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err := checkAPDUResponse(response)
+	if err != nil {
+		return err
+	}
+	responseData = append(responseData, data...)
+
+	tag, value, err := readTLV(responseData)
+	if err != nil {
+		return err
+	}
+	if tag != tlvTagData {
+		return ErrInvalidTLV
+	}
+	if len(value) > maxMessageLength {
+		return ErrMessageTooLong
+	}
+
+	n.response = value
+	return nil
+}
+
+// Receive returns the response to the previous Send.
+func (n *NFC) Receive() ([]byte, error) {
+	if n.response == nil {
+		return nil, ErrMissingResponse
+	}
+
+	response := n.response
+	n.response = nil
+	return response, nil
+}
+
+// Close releases the transport, the session itself is ended by sending SessionData with
+// a termination status.
+func (n *NFC) Close() error {
+	n.response = nil
+	return nil
+}
+
+func (n *NFC) command(cla byte, ins byte, data []byte, expectResponse bool) []byte {
+	command := []byte{cla, ins, 0x00, 0x00}
+
+	if n.extended {
+		if len(data) > 0 || expectResponse {
+			command = append(command, 0x00)
+		}
+		if len(data) > 0 {
+			command = append(command, byte(len(data)>>8), byte(len(data)))
+			command = append(command, data...)
+		}
+		if expectResponse {
+			le := n.maxLengthResponseData
+			command = append(command, byte(le>>8), byte(le))
+		}
+		return command
+	}
+
+	if len(data) > 0 {
+		command = append(command, byte(len(data)))
+		command = append(command, data...)
+	}
+	if expectResponse {
+		command = append(command, byte(n.maxLengthResponseData))
+	}
+	return command
+}
+
+func checkAPDUResponse(response []byte) ([]byte, error) {
+	if len(response) < 2 {
+		return nil, ErrInvalidAPDU
+	}
+	data := response[:len(response)-2]
+	if [2]byte(response[len(response)-2:]) != apduSWSuccess {
+		return nil, ErrUnexpectedStatusWord
+	}
+	return data, nil
+}
+
+func appendTLV(to []byte, tag byte, value []byte) []byte {
+	to = append(to, tag)
+
+	length := len(value)
+	switch {
+	case length < 0x80:
+		to = append(to, byte(length))
+	case length <= 0xff:
+		to = append(to, 0x81, byte(length))
+	case length <= 0xffff:
+		to = append(to, 0x82, byte(length>>8), byte(length))
+	case length <= 0xffffff:
+		to = append(to, 0x83, byte(length>>16), byte(length>>8), byte(length))
+	default:
+		to = append(to, 0x84, byte(length>>24), byte(length>>16), byte(length>>8), byte(length))
+	}
+
+	return append(to, value...)
+}
+
+func readTLV(from []byte) (byte, []byte, error) {
+	if len(from) < 2 {
+		return 0, nil, ErrInvalidTLV
+	}
+
+	tag := from[0]
+	length := int(from[1])
+	from = from[2:]
+
+	if length > 0x80 {
+		lengthLength := length - 0x80
+		if lengthLength > 4 || len(from) < lengthLength {
+			return 0, nil, ErrInvalidTLV
+		}
+		length = 0
+		for _, b := range from[:lengthLength] {
+			length = length<<8 | int(b)
+		}
+		from = from[lengthLength:]
+	} else if length == 0x80 {
+		return 0, nil, ErrInvalidTLV
+	}
+
+	if len(from) != length {
+		return 0, nil, ErrInvalidTLV
+	}
+
+	return tag, from, nil
+}
@@ -0,0 +1,186 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/alex-richards/go-mdoc"
+)
+
+type scriptedAPDU struct {
+	command  string
+	response string
+}
+
+// scriptedAPDUExchanger is a fake holder device replaying a fixed APDU exchange.
+type scriptedAPDUExchanger struct {
+	t      *testing.T
+	script []scriptedAPDU
+}
+
+func (e *scriptedAPDUExchanger) Exchange(command []byte) ([]byte, error) {
+	e.t.Helper()
+
+	if len(e.script) == 0 {
+		e.t.Fatalf("unexpected command %x", command)
+	}
+	next := e.script[0]
+	e.script = e.script[1:]
+
+	if got := hex.EncodeToString(command); got != next.command {
+		e.t.Fatalf("expected command %s, got %s", next.command, got)
+	}
+
+	response, err := hex.DecodeString(next.response)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return response, nil
+}
+
+func Test_NFC(t *testing.T) {
+	tests := []struct {
+		name             string
+		options          mdoc.NFCOptions
+		maxMessageLength int
+		request          []byte
+		script           []scriptedAPDU
+		want             []byte
+		wantErr          error
+	}{
+		{
+			name:    "Single command",
+			options: mdoc.NFCOptions{MaxLengthCommandData: 255, MaxLengthResponseData: 256},
+			request: []byte("hello"),
+			script: []scriptedAPDU{
+				{command: "00c30000075305" + hex.EncodeToString([]byte("hello")) + "00", response: "5303" + hex.EncodeToString([]byte("abc")) + "9000"},
+			},
+			want: []byte("abc"),
+		},
+		{
+			name:    "Chaining and GET RESPONSE",
+			options: mdoc.NFCOptions{MaxLengthCommandData: 5, MaxLengthResponseData: 4},
+			request: []byte("hello"),
+			script: []scriptedAPDU{
+				{command: "10c30000055305" + hex.EncodeToString([]byte("hel")), response: "9000"},
+				{command: "00c3000002" + hex.EncodeToString([]byte("lo")) + "04", response: "5303" + hex.EncodeToString([]byte("ab")) + "6101"},
+				{command: "00c0000004", response: hex.EncodeToString([]byte("c")) + "9000"},
+			},
+			want: []byte("abc"),
+		},
+		{
+			name:    "Extended length",
+			options: mdoc.NFCOptions{MaxLengthCommandData: 1000, MaxLengthResponseData: 1000},
+			request: bytes.Repeat([]byte{0xaa}, 300),
+			script: []scriptedAPDU{
+				{command: "00c300000001305382012c" + hex.EncodeToString(bytes.Repeat([]byte{0xaa}, 300)) + "03e8", response: "538100" + "6100"},
+				{command: "00c00000" + "0003e8", response: "9000"},
+			},
+			want: []byte{},
+		},
+		{
+			name:             "Response too long",
+			options:          mdoc.NFCOptions{MaxLengthCommandData: 5, MaxLengthResponseData: 4},
+			maxMessageLength: 2,
+			request:          []byte("hello"),
+			script: []scriptedAPDU{
+				{command: "10c30000055305" + hex.EncodeToString([]byte("hel")), response: "9000"},
+				{command: "00c3000002" + hex.EncodeToString([]byte("lo")) + "04", response: "5303" + hex.EncodeToString([]byte("ab")) + "6101"},
+				{command: "00c0000004", response: hex.EncodeToString([]byte("c")) + "9000"},
+			},
+			wantErr: ErrMessageTooLong,
+		},
+		{
+			name:             "Endless GET RESPONSE",
+			options:          mdoc.NFCOptions{MaxLengthCommandData: 255, MaxLengthResponseData: 256},
+			maxMessageLength: 2,
+			request:          []byte("hello"),
+			script: []scriptedAPDU{
+				{command: "00c30000075305" + hex.EncodeToString([]byte("hello")) + "00", response: "530a" + hex.EncodeToString(make([]byte, 8)) + "6100"},
+			},
+			wantErr: ErrMessageTooLong,
+		},
+		{
+			name:    "Error status",
+			options: mdoc.NFCOptions{MaxLengthCommandData: 255, MaxLengthResponseData: 256},
+			request: []byte("hello"),
+			script: []scriptedAPDU{
+				{command: "00c30000075305" + hex.EncodeToString([]byte("hello")) + "00", response: "6a82"},
+			},
+			wantErr: ErrUnexpectedStatusWord,
+		},
+		{
+			name:    "Invalid TLV",
+			options: mdoc.NFCOptions{MaxLengthCommandData: 255, MaxLengthResponseData: 256},
+			request: []byte("hello"),
+			script: []scriptedAPDU{
+				{command: "00c30000075305" + hex.EncodeToString([]byte("hello")) + "00", response: "5405" + hex.EncodeToString([]byte("abc")) + "9000"},
+			},
+			wantErr: ErrInvalidTLV,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exchanger := &scriptedAPDUExchanger{
+				t: t,
+				script: append([]scriptedAPDU{
+					{command: "00a4040c07a0000002480400", response: "9000"},
+				}, tt.script...),
+			}
+
+			nfc, err := NewNFC(exchanger, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			nfc.MaxMessageLength = tt.maxMessageLength
+
+			if err = nfc.Select(); err != nil {
+				t.Fatal(err)
+			}
+
+			err = nfc.Send(tt.request)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			response, err := nfc.Receive()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(tt.want, response) {
+				t.Fatalf("expected %x, got %x", tt.want, response)
+			}
+
+			if len(exchanger.script) != 0 {
+				t.Fatalf("%d commands not sent", len(exchanger.script))
+			}
+
+			if _, err = nfc.Receive(); !errors.Is(err, ErrMissingResponse) {
+				t.Fatalf("expected %v, got %v", ErrMissingResponse, err)
+			}
+		})
+	}
+}
+
+func Test_TLV_RoundTrip(t *testing.T) {
+	for _, length := range []int{0, 0x7f, 0x80, 0xff, 0x100, 0xffff, 0x10000} {
+		value := bytes.Repeat([]byte{0x01}, length)
+
+		tag, got, err := readTLV(appendTLV(nil, tlvTagData, value))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tag != tlvTagData || !bytes.Equal(value, got) {
+			t.Fatalf("round trip failed for length %d", length)
+		}
+	}
+}
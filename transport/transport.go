@@ -0,0 +1,24 @@
+package transport
+
+import (
+	"errors"
+)
+
+var (
+	ErrSessionEnded   = errors.New("mdoc: transport: session ended")
+	ErrMessageTooLong = errors.New("mdoc: transport: message too long")
+)
+
+// DefaultMaxMessageLength is the longest message received when no maximum is set.
+const DefaultMaxMessageLength = 16 << 20
+
+// Transport sends and receives complete session messages, SessionEstablishment and
+// SessionData, between the holder and the reader.
+type Transport interface {
+	Send(message []byte) error
+	// Receive blocks until a complete message is received, returning ErrSessionEnded if the
+	// other party ended the session.
+	Receive() ([]byte, error)
+	// Close ends the session and releases the underlying connection.
+	Close() error
+}
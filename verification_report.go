@@ -0,0 +1,143 @@
+package mdoc
+
+import (
+	"sort"
+)
+
+// ElementResults holds the result of a check for each data element, nil if it passed.
+type ElementResults map[NameSpace]map[DataElementIdentifier]error
+
+// set records the result for a data element, keeping any earlier failure.
+func (er ElementResults) set(nameSpace NameSpace, dataElementIdentifier DataElementIdentifier, err error) {
+	dataElements, ok := er[nameSpace]
+	if !ok {
+		dataElements = make(map[DataElementIdentifier]error)
+		er[nameSpace] = dataElements
+	}
+	if dataElements[dataElementIdentifier] != nil {
+		return
+	}
+	dataElements[dataElementIdentifier] = err
+}
+
+// Err returns the first failure, ordered by name space then data element identifier.
+func (er ElementResults) Err() error {
+	nameSpaces := make([]NameSpace, 0, len(er))
+	for nameSpace := range er {
+		nameSpaces = append(nameSpaces, nameSpace)
+	}
+	sort.Slice(nameSpaces, func(i, j int) bool { return nameSpaces[i] < nameSpaces[j] })
+
+	for _, nameSpace := range nameSpaces {
+		dataElements := er[nameSpace]
+		dataElementIdentifiers := make([]DataElementIdentifier, 0, len(dataElements))
+		for dataElementIdentifier := range dataElements {
+			dataElementIdentifiers = append(dataElementIdentifiers, dataElementIdentifier)
+		}
+		sort.Slice(dataElementIdentifiers, func(i, j int) bool { return dataElementIdentifiers[i] < dataElementIdentifiers[j] })
+
+		for _, dataElementIdentifier := range dataElementIdentifiers {
+			if err := dataElements[dataElementIdentifier]; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Valid reports whether the check passed for a data element.
+func (er ElementResults) Valid(nameSpace NameSpace, dataElementIdentifier DataElementIdentifier) bool {
+	err, ok := er[nameSpace][dataElementIdentifier]
+	return ok && err == nil
+}
+
+// VerificationReport records the result of each check made while verifying a Document,
+// so that partial results can be shown when some checks fail.
+type VerificationReport struct {
+	DocType DocType
+
+	// Policy is the first VerifierPolicy rule the document violated.
+	Policy error
+
+	// IssuerAuth is the result of verifying the document signer certificate chain and the
+	// MobileSecurityObject signature.
+	IssuerAuth error
+	// MobileSecurityObject is the result of checking the MobileSecurityObject version and
+	// that its DocType matches the Document.
+	MobileSecurityObject error
+	// Validity is the result of checking the MobileSecurityObject ValidityInfo.
+	Validity error
+	// Digests are the results of checking each IssuerSignedItem against its value digest.
+	Digests ElementResults
+	// Types are the results of checking each IssuerSignedItem against the VerifierPolicy
+	// Schema, and are only set when a Schema is configured.
+	Types ElementResults
+
+	// DeviceAuth is the result of verifying the DeviceSignature or DeviceMAC.
+	DeviceAuth error
+	// KeyAuthorizations are the results of checking each device signed data element is
+	// authorized by the MobileSecurityObject.
+	KeyAuthorizations ElementResults
+
+	// Warnings are conditions that don't invalidate the document but may be shown to the
+	// user, such as ErrExpectedUpdatePassed.
+	Warnings []error
+}
+
+func newVerificationReport(docType DocType) *VerificationReport {
+	return &VerificationReport{
+		DocType:           docType,
+		Digests:           make(ElementResults),
+		Types:             make(ElementResults),
+		KeyAuthorizations: make(ElementResults),
+	}
+}
+
+// Err returns the first failed check, or nil if the document is valid.
+func (vr *VerificationReport) Err() error {
+	if vr.Policy != nil {
+		return vr.Policy
+	}
+	if vr.IssuerAuth != nil {
+		return vr.IssuerAuth
+	}
+	if vr.MobileSecurityObject != nil {
+		return vr.MobileSecurityObject
+	}
+	if vr.Validity != nil {
+		return vr.Validity
+	}
+	if err := vr.Digests.Err(); err != nil {
+		return err
+	}
+	if err := vr.Types.Err(); err != nil {
+		return err
+	}
+	if vr.DeviceAuth != nil {
+		return vr.DeviceAuth
+	}
+	return vr.KeyAuthorizations.Err()
+}
+
+func (vr *VerificationReport) violatePolicy(err error) {
+	if vr.Policy == nil {
+		vr.Policy = err
+	}
+}
+
+// DeviceRequestVerificationReport records the reader authentication result for each
+// DocRequest, in the same order as DeviceRequest.DocRequests.
+type DeviceRequestVerificationReport struct {
+	ReaderAuths []error
+}
+
+// Err returns the first failed check, or nil if every DocRequest is authenticated.
+func (drvr *DeviceRequestVerificationReport) Err() error {
+	for _, err := range drvr.ReaderAuths {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
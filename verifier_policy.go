@@ -0,0 +1,112 @@
+package mdoc
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/veraison/go-cose"
+)
+
+var (
+	ErrDigestAlgorithmNotAllowed = errors.New("mdoc: digest algorithm not allowed by policy")
+	ErrAlgorithmNotAllowed       = errors.New("mdoc: algorithm not allowed by policy")
+	ErrCurveNotAllowed           = errors.New("mdoc: curve not allowed by policy")
+	ErrDocTypeNotAllowed         = errors.New("mdoc: doc type not allowed by policy")
+	ErrDeviceSignedNotAllowed    = errors.New("mdoc: device signed data elements not allowed by policy")
+)
+
+// DataElementValidator checks encoded data element values, such as a schema.Registry.
+type DataElementValidator interface {
+	ValidateCBOR(nameSpace NameSpace, dataElementIdentifier DataElementIdentifier, data []byte) error
+}
+
+// VerifierPolicy configures which documents and requests are accepted during verification.
+// The zero value accepts anything the library can verify, with no clock skew and optional
+// reader authentication.
+type VerifierPolicy struct {
+	// DigestAlgorithms are the MobileSecurityObject digest algorithms accepted, or all
+	// supported algorithms if empty.
+	DigestAlgorithms []DigestAlgorithm
+	// Algorithms are the COSE signature algorithms accepted for IssuerAuth, DeviceSignature
+	// and ReaderAuth, or all supported algorithms if empty.
+	Algorithms []cose.Algorithm
+	// Curves are the curves accepted for the DeviceKey, or all supported curves if empty.
+	Curves []Curve
+	// DocTypes are the document types accepted, or any DocType if empty.
+	DocTypes []DocType
+
+	// Schema, if set, checks the type of every issuer signed data element.
+	Schema DataElementValidator
+
+	// ClockSkew is the tolerance applied to the MobileSecurityObject ValidityInfo.
+	ClockSkew time.Duration
+
+	// RejectDeviceSigned rejects documents containing device signed data elements.
+	RejectDeviceSigned bool
+	// RequireReaderAuth rejects DocRequests without ReaderAuth.
+	RequireReaderAuth bool
+}
+
+// DefaultVerifierPolicy returns the policy used by Verify, and in place of a nil policy,
+// which accepts any supported algorithm and requires reader authentication.
+func DefaultVerifierPolicy() *VerifierPolicy {
+	return &VerifierPolicy{
+		ClockSkew:         DefaultClockSkew,
+		RequireReaderAuth: true,
+	}
+}
+
+// orDefault returns vp, or DefaultVerifierPolicy if vp is nil.
+func (vp *VerifierPolicy) orDefault() *VerifierPolicy {
+	if vp == nil {
+		return DefaultVerifierPolicy()
+	}
+	return vp
+}
+
+func (vp *VerifierPolicy) checkDigestAlgorithm(digestAlgorithm DigestAlgorithm) error {
+	if len(vp.DigestAlgorithms) > 0 && !slices.Contains(vp.DigestAlgorithms, digestAlgorithm) {
+		return ErrDigestAlgorithmNotAllowed
+	}
+	return nil
+}
+
+func (vp *VerifierPolicy) checkAlgorithm(headers *cose.Headers) error {
+	if len(vp.Algorithms) == 0 {
+		return nil
+	}
+
+	algorithm, err := headers.Protected.Algorithm()
+	if err != nil {
+		return ErrMissingAlgorithmHeader
+	}
+
+	if !slices.Contains(vp.Algorithms, algorithm) {
+		return ErrAlgorithmNotAllowed
+	}
+	return nil
+}
+
+func (vp *VerifierPolicy) checkCurve(publicKey *PublicKey) error {
+	if len(vp.Curves) == 0 {
+		return nil
+	}
+
+	curve, err := publicKey.Curve()
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(vp.Curves, curve) {
+		return ErrCurveNotAllowed
+	}
+	return nil
+}
+
+func (vp *VerifierPolicy) checkDocType(docType DocType) error {
+	if len(vp.DocTypes) > 0 && !slices.Contains(vp.DocTypes, docType) {
+		return ErrDocTypeNotAllowed
+	}
+	return nil
+}